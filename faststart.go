@@ -0,0 +1,72 @@
+package main
+
+import (
+    "bufio"
+    "flag"
+    "fmt"
+    "os"
+    ol "github.com/ossrs/go-oryx-lib/logger"
+    "github.com/panda1986/mp4_parser/mp4"
+)
+
+// Rewrite the mp4 with moov ahead of mdat, for fast start over http, for example:
+//      mp4_parser faststart -input in.mp4 -output out.mp4
+// The output is written to a temporary file, which is renamed when done.
+func faststart(args []string) (err error) {
+    fs := flag.NewFlagSet("faststart", flag.ExitOnError)
+    input := fs.String("input", "", "the mp4 file to rewrite")
+    output := fs.String("output", "", "the rewritten mp4 file")
+    fs.Parse(args)
+
+    if *input == "" || *output == "" {
+        return fmt.Errorf("input and output are required")
+    }
+    if *input == *output {
+        return fmt.Errorf("output %v must not be the input", *output)
+    }
+
+    var in *os.File
+    if in, err = os.Open(*input); err != nil {
+        return
+    }
+    defer in.Close()
+
+    var info os.FileInfo
+    if info, err = in.Stat(); err != nil {
+        return
+    }
+
+    var f *mp4.File
+    if f, err = mp4.ParseAt(in, info.Size()); err != nil {
+        return
+    }
+    if err = f.Faststart(); err != nil {
+        return
+    }
+
+    tmp := *output + ".tmp"
+    var out *os.File
+    if out, err = os.Create(tmp); err != nil {
+        return
+    }
+    defer os.Remove(tmp)
+    defer out.Close()
+
+    // The mdat is copied from the input by the buffer, never loaded into memory.
+    w := bufio.NewWriterSize(out, 1024 * 1024)
+    if err = f.Encode(w, in); err != nil {
+        return
+    }
+    if err = w.Flush(); err != nil {
+        return
+    }
+    if err = out.Close(); err != nil {
+        return
+    }
+    if err = os.Rename(tmp, *output); err != nil {
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("faststart %v to %v success", *input, *output))
+    return
+}
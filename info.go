@@ -0,0 +1,133 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "github.com/panda1986/mp4_parser/mp4"
+)
+
+// Print the human-readable summary of tracks, like ffprobe or mediainfo, for example:
+//      mp4_parser info -url test.mp4
+// The input is the local mp4 file or the http(s) url, the same as the json output.
+func info(args []string) (err error) {
+    fs := flag.NewFlagSet("info", flag.ExitOnError)
+    mp4Url := fs.String("url", "./test.mp4", "mp4 file to be summarized")
+    fs.Parse(args)
+
+    var f *mp4.File
+    if f, err = parse(*mp4Url); err != nil {
+        return
+    }
+
+    var moov *mp4.Mp4MovieBox
+    if moov, err = f.Moov(); err != nil {
+        return
+    }
+
+    w := os.Stdout
+    fmt.Fprintf(w, "File: %v\n", *mp4Url)
+    if mime, err := f.MimeType(); err == nil {
+        field(w, "MIME type", mime)
+    }
+    var movieTimeScale uint32
+    if mvhd, err := moov.Mvhd(); err == nil {
+        movieTimeScale = mvhd.TimeScale
+        field(w, "Duration", seconds(mvhd.DurationInTbn, mvhd.TimeScale))
+        field(w, "Creation time", creationTime(mvhd.CreateTime))
+    }
+    field(w, "Tracks", fmt.Sprintf("%v video, %v audio", moov.NbVideoTracks(), moov.NbSoundTracks()))
+
+    for _, trak := range moov.Tracks() {
+        fmt.Fprintln(w)
+        printTrack(w, moov, trak, movieTimeScale)
+    }
+    return
+}
+
+func printTrack(w io.Writer, moov *mp4.Mp4MovieBox, trak *mp4.Mp4TrackBox, movieTimeScale uint32) {
+    summary := mp4.NewTrackJSON(moov, trak)
+    fmt.Fprintf(w, "Track #%v\n", summary.TrackId)
+
+    handler := summary.Handler
+    if hdlr, err := trak.Hdlr(); err == nil && hdlr.Name != "" {
+        handler = fmt.Sprintf("%v (%v)", handler, hdlr.Name)
+    }
+    field(w, "Handler", handler)
+
+    codec := summary.Codec
+    if summary.Codecs != "" && summary.Codecs != summary.Codec {
+        codec = fmt.Sprintf("%v (%v)", codec, summary.Codecs)
+    }
+    if summary.Description != "" {
+        codec = fmt.Sprintf("%v, %v", codec, summary.Description)
+    }
+    field(w, "Codec", codec)
+
+    field(w, "Duration", seconds(summary.Duration, summary.TimeScale))
+    if summary.MediaDuration != summary.Duration {
+        field(w, "Media duration", seconds(summary.MediaDuration, summary.TimeScale))
+    }
+    field(w, "Samples", summary.SampleCount)
+
+    if trak.TrackType() == mp4.SrsMp4TrackTypeVideo {
+        if tkhd, err := trak.Tkhd(); err == nil {
+            resolution := fmt.Sprintf("%vx%v", tkhd.DisplayWidth(), tkhd.DisplayHeight())
+            if float64(summary.Width) != tkhd.DisplayWidth() || float64(summary.Height) != tkhd.DisplayHeight() {
+                resolution = fmt.Sprintf("%v (coded %vx%v)", resolution, summary.Width, summary.Height)
+            }
+            field(w, "Resolution", resolution)
+        }
+        if fps, err := trak.FrameRate(); err == nil {
+            field(w, "Frame rate", fmt.Sprintf("%.3f fps", fps))
+        }
+    }
+
+    if trak.TrackType() == mp4.SrsMp4TrackTypeAudio {
+        field(w, "Sample rate", fmt.Sprintf("%v Hz", summary.SampleRate))
+        field(w, "Channels", summary.ChannelCount)
+    }
+
+    if bitrate, err := trak.Bitrate(); err == nil {
+        field(w, "Bitrate", fmt.Sprintf("%v kb/s", strconv.FormatFloat(float64(bitrate) / 1000, 'f', 1, 64)))
+    }
+    if mdhd, err := trak.Mdhd(); err == nil {
+        field(w, "Language", mdhd.LanguageCode())
+        field(w, "Creation time", creationTime(mdhd.CreateTime))
+    }
+    if offset, err := trak.StartOffset(movieTimeScale); err == nil && offset != 0 {
+        field(w, "Start offset", fmt.Sprintf("%v (%v)", offset, seconds(uint64(abs(offset)), summary.TimeScale)))
+    }
+    if len(summary.ExternalData) > 0 {
+        field(w, "External data", summary.ExternalData)
+    }
+}
+
+func field(w io.Writer, name string, value interface{}) {
+    fmt.Fprintf(w, "    %-16v: %v\n", name, value)
+}
+
+// Format the duration in timescale to seconds, for example, 10.010 s.
+func seconds(duration uint64, timescale uint32) string {
+    if timescale == 0 {
+        return "unknown"
+    }
+    return fmt.Sprintf("%.3f s", float64(duration) / float64(timescale))
+}
+
+// Format the time since 1904 in UTC, 0 for unknown.
+func creationTime(t uint64) string {
+    if t == 0 {
+        return "unknown"
+    }
+    return mp4.Mp4Time(t).Format("2006-01-02 15:04:05 UTC")
+}
+
+func abs(v int64) int64 {
+    if v < 0 {
+        return -v
+    }
+    return v
+}
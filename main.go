@@ -1,57 +1,97 @@
 package main
 
 import (
+    "encoding/json"
     "fmt"
     "flag"
+    "net/http"
     "os"
+    "strings"
+    "time"
     ol "github.com/ossrs/go-oryx-lib/logger"
-    "io"
+    "github.com/panda1986/mp4_parser/mp4"
 )
 
 const (
     version = "0.0.2"
-
-    SRS_MP4_EOF_SIZE = 0
-    SRS_MP4_USE_LARGE_SIZE = 1
 )
 
 func main()  {
-    fmt.Println(fmt.Sprintf("mp4 parser:%v, by panda of bravovcloud.com", version))
+    // The stdout is for the json output, so log to stderr.
+    ol.Switch(os.Stderr)
+    ol.T(nil, fmt.Sprintf("mp4 parser:%v, by panda of bravovcloud.com", version))
+
+    if len(os.Args) > 1 && os.Args[1] == "serve" {
+        if err := serve(os.Args[2:]); err != nil {
+            ol.E(nil, fmt.Sprintf("serve failed, err is %v", err))
+            os.Exit(1)
+        }
+        return
+    }
+
+    if len(os.Args) > 1 && os.Args[1] == "faststart" {
+        if err := faststart(os.Args[2:]); err != nil {
+            ol.E(nil, fmt.Sprintf("faststart failed, err is %v", err))
+            os.Exit(1)
+        }
+        return
+    }
+
+    if len(os.Args) > 1 && os.Args[1] == "info" {
+        if err := info(os.Args[2:]); err != nil {
+            ol.E(nil, fmt.Sprintf("info failed, err is %v", err))
+            os.Exit(1)
+        }
+        return
+    }
 
     var mp4Url string
     flag.StringVar(&mp4Url, "url", "./test.mp4", "mp4 file to be parsed")
+    flag.Parse()
 
     ol.T(nil, "the input mp4 url is:", mp4Url)
 
-    var f * os.File
+    var file *mp4.File
     var err error
-    if f, err = os.Open(mp4Url); err != nil {
-        ol.T(nil, fmt.Sprint("open file:%v failed, err is %v", mp4Url, err))
+    if file, err = parse(mp4Url); err != nil {
+        ol.E(nil, fmt.Sprintf("decode mp4 file:%v failed, err is %v", mp4Url, err))
         return
     }
 
-    for {
-        mb := NewMp4Box()
-        var box Box
-        if box, err = mb.discovery(f); err != nil {
-            ol.E(nil, fmt.Sprintf("discovery box failed, err is %v", err))
-            break
+    ol.T(nil, fmt.Sprintf("decode mp4 file:%v success, boxes=%v", mp4Url, len(file.Boxes)))
+    if moov, err := file.Moov(); err == nil {
+        for i, trak := range moov.Tracks() {
+            if locations := trak.ExternalData(); len(locations) > 0 {
+                ol.W(nil, fmt.Sprintf("track #%v references external data %v, samples are not in this file", i, locations))
+            }
         }
-        ol.T(nil, fmt.Sprintf("main discovery box:%+v", box))
+    }
 
-        if err = box.DecodeHeader(f); err != nil {
-            ol.E(nil, fmt.Sprintf("mp4 decode contained box header failed, err is %v", err))
-            break
-        }
-        ol.T(nil, fmt.Sprintf("main after decode header, box:%+v", box))
+    enc := json.NewEncoder(os.Stdout)
+    enc.SetIndent("", "  ")
+    if err = enc.Encode(file); err != nil {
+        ol.E(nil, fmt.Sprintf("encode mp4 file:%v to json failed, err is %v", mp4Url, err))
+        return
+    }
+}
 
-        if err = box.Basic().DecodeBoxes(f); err != nil {
-            ol.E(nil, fmt.Sprintf("mp4 decode contained box boxes failed, err is %v", err))
-            break
+// Parse the local mp4 file, or the remote one over http(s) by range requests.
+func parse(mp4Url string) (file *mp4.File, err error) {
+    if strings.HasPrefix(mp4Url, "http://") || strings.HasPrefix(mp4Url, "https://") {
+        client := &http.Client{Timeout: 30 * time.Second}
+        var r *mp4.HttpReader
+        if r, err = mp4.NewHttpReader(client, mp4Url); err != nil {
+            return
         }
+        return mp4.ParseAt(r, r.Size())
     }
 
-    if err == io.EOF {
-        ol.T(nil, fmt.Sprintf("decode mp4 file:%v success", mp4Url))
+    var f *os.File
+    if f, err = os.Open(mp4Url); err != nil {
+        ol.E(nil, fmt.Sprintf("open file:%v failed, err is %v", mp4Url, err))
+        return
     }
-}
\ No newline at end of file
+    defer f.Close()
+
+    return mp4.Parse(f)
+}
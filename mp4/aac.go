@@ -0,0 +1,235 @@
+package mp4
+
+import (
+    "fmt"
+    "strconv"
+)
+
+/**
+ * 1.6.2.1 AudioSpecificConfig
+ * ISO_IEC_14496-3-AAC-2001.pdf, page 27
+ * The SBR and PS are detected by the explicit signalling, the hierarchical one by object type 5 or 29, or
+ * the backward compatible one by the sync extension. The implicit signalling is in the raw data, which
+ * is not detected, so the SamplingFrequency is the rate of the core AAC.
+ */
+type Mp4AacConfig struct {
+    // The object type of the core, for example, SrsAacObjectTypeAacLC for HE-AAC.
+    ObjectType uint8
+    SamplingFrequencyIndex uint8
+    SamplingFrequency uint32
+    // The channel configuration, 0 for the program_config_element, which is not decoded.
+    ChannelConfiguration uint8
+    // The SBR, with the output sampling frequency.
+    Sbr bool
+    ExtensionSamplingFrequency uint32
+    Ps bool
+}
+
+// Decode the AudioSpecificConfig.
+func NewMp4AacConfig(asc []uint8) (v *Mp4AacConfig, err error) {
+    v = &Mp4AacConfig{}
+    br := newBitReader(asc)
+
+    var objectType uint8
+    if objectType, err = readAacObjectType(br); err != nil {
+        return
+    }
+    if v.SamplingFrequencyIndex, v.SamplingFrequency, err = readAacSamplingFrequency(br); err != nil {
+        return
+    }
+    if v.ChannelConfiguration, err = br.read8(4); err != nil {
+        return
+    }
+
+    // The hierarchical signalling, the core object type follows.
+    if objectType == SrsAacObjectTypeAacHE || objectType == SrsAacObjectTypeAacHEV2 {
+        v.Sbr, v.Ps = true, objectType == SrsAacObjectTypeAacHEV2
+        if _, v.ExtensionSamplingFrequency, err = readAacSamplingFrequency(br); err != nil {
+            return
+        }
+        if objectType, err = readAacObjectType(br); err != nil {
+            return
+        }
+    }
+    v.ObjectType = objectType
+
+    // The GASpecificConfig, to find the sync extension after it.
+    switch objectType {
+    case 1, 2, 3, 4, 6, 7:
+    default:
+        return
+    }
+    // The frameLengthFlag and dependsOnCoreCoder.
+    var u uint32
+    if u, err = br.read(2); err != nil {
+        return v, nil
+    }
+    if u & 0x01 != 0 {
+        // The coreCoderDelay.
+        if _, err = br.read(14); err != nil {
+            return v, nil
+        }
+    }
+    // The extensionFlag, which is 0 for these object types.
+    if _, err = br.read(1); err != nil {
+        return v, nil
+    }
+    if v.ChannelConfiguration == 0 {
+        return
+    }
+    if objectType == 6 {
+        // The layerNr.
+        if _, err = br.read(3); err != nil {
+            return v, nil
+        }
+    }
+
+    // The backward compatible signalling, 1.6.6 Signaling of SBR, page 37.
+    if !v.Sbr && br.left() >= 16 {
+        if u, _ = br.read(11); u != 0x2b7 {
+            return
+        }
+        var extensionObjectType uint8
+        if extensionObjectType, err = readAacObjectType(br); err != nil || extensionObjectType != SrsAacObjectTypeAacHE {
+            return v, nil
+        }
+        if u, err = br.read(1); err != nil || u == 0 {
+            return v, nil
+        }
+        v.Sbr = true
+        if _, v.ExtensionSamplingFrequency, err = readAacSamplingFrequency(br); err != nil {
+            return v, nil
+        }
+        if br.left() >= 12 {
+            if u, _ = br.read(11); u == 0x548 {
+                u, _ = br.read(1)
+                v.Ps = u != 0
+            }
+        }
+    }
+    return v, nil
+}
+
+// Read the audioObjectType, with the escape value.
+func readAacObjectType(br *bitReader) (objectType uint8, err error) {
+    if objectType, err = br.read8(5); err != nil {
+        return
+    }
+    if objectType == SrsAacObjectTypeEscape {
+        var ext uint8
+        if ext, err = br.read8(6); err != nil {
+            return
+        }
+        objectType = 32 + ext
+    }
+    return
+}
+
+// Read the samplingFrequencyIndex, with the explicit frequency for index 0xf.
+func readAacSamplingFrequency(br *bitReader) (index uint8, frequency uint32, err error) {
+    if index, err = br.read8(4); err != nil {
+        return
+    }
+    if index == 0x0f {
+        frequency, err = br.read(24)
+        return
+    }
+
+    frequencies := []uint32{96000, 88200, 64000, 48000, 44100, 32000, 24000, 22050, 16000, 12000, 11025, 8000, 7350}
+    if int(index) >= len(frequencies) {
+        return index, 0, fmt.Errorf("invalid sampling frequency index %v", index)
+    }
+    return index, frequencies[index], nil
+}
+
+// Get the profile of ADTS, for example, SrsAacProfileLC for HE-AAC, whose core is AAC-LC.
+func (v *Mp4AacConfig) Profile() int {
+    switch v.ObjectType {
+    case SrsAacObjectTypeAacMain:
+        return SrsAacProfileMain
+    case SrsAacObjectTypeAacLC:
+        return SrsAacProfileLC
+    case SrsAacObjectTypeAacSSR:
+        return SrsAacProfileSSR
+    }
+    return SrsAacProfileReserved
+}
+
+// Get the object type in RFC 6381, for example, 2 for AAC-LC, 5 for HE-AAC and 29 for HE-AACv2.
+func (v *Mp4AacConfig) SignalledObjectType() uint8 {
+    if v.Ps {
+        return SrsAacObjectTypeAacHEV2
+    }
+    if v.Sbr {
+        return SrsAacObjectTypeAacHE
+    }
+    return v.ObjectType
+}
+
+// Get the output sample rate, the rate of SBR is usually double of the core.
+func (v *Mp4AacConfig) SampleRate() uint32 {
+    if v.Sbr && v.ExtensionSamplingFrequency != 0 {
+        return v.ExtensionSamplingFrequency
+    }
+    return v.SamplingFrequency
+}
+
+// Get the output channels, the PS makes mono to stereo, 0 for unknown.
+func (v *Mp4AacConfig) Channels() int {
+    if v.Ps && v.ChannelConfiguration == 1 {
+        return 2
+    }
+    channels := []int{0, 1, 2, 3, 4, 5, 6, 8}
+    if int(v.ChannelConfiguration) < len(channels) {
+        return channels[v.ChannelConfiguration]
+    }
+    return 0
+}
+
+// Get the name of object type, for example, AAC-LC.
+func (v *Mp4AacConfig) Name() string {
+    if v.Ps {
+        return "HE-AACv2"
+    }
+    if v.Sbr {
+        return "HE-AAC"
+    }
+    switch v.ObjectType {
+    case SrsAacObjectTypeAacMain:
+        return "AAC Main"
+    case SrsAacObjectTypeAacLC:
+        return "AAC-LC"
+    case SrsAacObjectTypeAacSSR:
+        return "AAC SSR"
+    case SrsAacObjectTypeAacLTP:
+        return "AAC LTP"
+    }
+    return fmt.Sprintf("AAC object type %v", v.ObjectType)
+}
+
+// Get the layout of channels, for example, stereo or 5.1.
+func (v *Mp4AacConfig) Layout() string {
+    switch v.Channels() {
+    case 1:
+        return "mono"
+    case 2:
+        return "stereo"
+    case 3:
+        return "3.0"
+    case 4:
+        return "4.0"
+    case 5:
+        return "5.0"
+    case 6:
+        return "5.1"
+    case 8:
+        return "7.1"
+    }
+    return "unknown channels"
+}
+
+// Get the description, for example, AAC-LC 48 kHz stereo.
+func (v *Mp4AacConfig) String() string {
+    rate := strconv.FormatFloat(float64(v.SampleRate()) / 1000, 'f', -1, 64)
+    return fmt.Sprintf("%v %v kHz %v", v.Name(), rate, v.Layout())
+}
@@ -0,0 +1,71 @@
+package mp4
+
+import (
+    "encoding/hex"
+    "testing"
+)
+
+func TestAacConfig(t *testing.T) {
+    cases := []struct {
+        asc string
+        objectType, signalled uint8
+        rate uint32
+        channels int
+        profile int
+        description string
+    }{
+        {"1190", SrsAacObjectTypeAacLC, SrsAacObjectTypeAacLC, 48000, 2, SrsAacProfileLC, "AAC-LC 48 kHz stereo"},
+        {"1208", SrsAacObjectTypeAacLC, SrsAacObjectTypeAacLC, 44100, 1, SrsAacProfileLC, "AAC-LC 44.1 kHz mono"},
+        {"11b0", SrsAacObjectTypeAacLC, SrsAacObjectTypeAacLC, 48000, 6, SrsAacProfileLC, "AAC-LC 48 kHz 5.1"},
+        {"0a10", SrsAacObjectTypeAacMain, SrsAacObjectTypeAacMain, 44100, 2, SrsAacProfileMain, "AAC Main 44.1 kHz stereo"},
+        // The explicit sampling frequency of 24 bits.
+        {"17805dc010", SrsAacObjectTypeAacLC, SrsAacObjectTypeAacLC, 48000, 2, SrsAacProfileLC, "AAC-LC 48 kHz stereo"},
+        // The hierarchical signalling of HE-AACv2, the core is AAC-LC 24 kHz mono.
+        {"eb098800", SrsAacObjectTypeAacLC, SrsAacObjectTypeAacHEV2, 48000, 2, SrsAacProfileLC, "HE-AACv2 48 kHz stereo"},
+        // The backward compatible signalling of HE-AAC by the sync extension, the core is AAC-LC 24 kHz.
+        {"131056e598", SrsAacObjectTypeAacLC, SrsAacObjectTypeAacHE, 48000, 2, SrsAacProfileLC, "HE-AAC 48 kHz stereo"},
+        // The escape object type of 32, which is MPEG-1/2 Layer-1, and the channels in program_config_element.
+        {"f80000000000", 32, 32, 96000, 0, SrsAacProfileReserved, "AAC object type 32 96 kHz unknown channels"},
+    }
+    for _, c := range cases {
+        asc, _ := hex.DecodeString(c.asc)
+        v, err := NewMp4AacConfig(asc)
+        if err != nil {
+            t.Errorf("%v: %v", c.asc, err)
+            continue
+        }
+        if v.ObjectType != c.objectType || v.SignalledObjectType() != c.signalled || v.SampleRate() != c.rate ||
+            v.Channels() != c.channels || v.Profile() != c.profile || v.String() != c.description {
+            t.Errorf("%v: config %+v, signalled %v, rate %v, channels %v, profile %v, %v", c.asc, v,
+                v.SignalledObjectType(), v.SampleRate(), v.Channels(), v.Profile(), v)
+        }
+    }
+
+    // The core of HE-AAC is in 24 kHz.
+    if v, _ := NewMp4AacConfig([]byte{0xeb, 0x09, 0x88, 0x00}); v.SamplingFrequency != 24000 || v.ChannelConfiguration != 1 || !v.Sbr || !v.Ps {
+        t.Errorf("HE-AACv2 core %+v", v)
+    }
+
+    for name, asc := range map[string]string{
+        "empty": "",
+        "short": "11",
+        // The sampling frequency index 13 is reserved.
+        "reserved frequency": "1680",
+        "short explicit frequency": "17805d",
+    } {
+        b, _ := hex.DecodeString(asc)
+        if _, err := NewMp4AacConfig(b); err == nil {
+            t.Errorf("%v: should fail", name)
+        }
+    }
+
+    // The summary of track takes the output rate and channels from the config, not the entry.
+    audio := newTestTrack(2, "soun", audioEntry("mp4a", 1, 16, 24000, esds(0x40, []byte{0xeb, 0x09, 0x88, 0x00})), 3)
+    moov, err := parseFile(t, buildFile(false, audio)).Moov()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if v := NewTrackJSON(moov, moov.Tracks()[0]); v.ChannelCount != 2 || v.SampleRate != 48000 || v.Description != "HE-AACv2 48 kHz stereo" {
+        t.Errorf("summary channels %v, rate %v, description %v", v.ChannelCount, v.SampleRate, v.Description)
+    }
+}
@@ -0,0 +1,413 @@
+package mp4
+
+import (
+    "fmt"
+)
+
+/**
+ * 7.3.2.1.1 Sequence parameter set data syntax
+ * ISO_IEC_14496-10-AVC-2012.pdf, page 62
+ * The SPS decoded to the stream parameters, the fields after the VUI timing are ignored.
+ */
+type Mp4AvcSps struct {
+    ProfileIdc uint8
+    // The constraint_set0_flag to constraint_set5_flag, from the MSB.
+    ConstraintFlags uint8
+    LevelIdc uint8
+    SeqParameterSetId uint32
+    ChromaFormatIdc uint32
+    SeparateColourPlaneFlag uint8
+    BitDepthLumaMinus8 uint32
+    BitDepthChromaMinus8 uint32
+    Log2MaxFrameNumMinus4 uint32
+    PicOrderCntType uint32
+    MaxNumRefFrames uint32
+    PicWidthInMbsMinus1 uint32
+    PicHeightInMapUnitsMinus1 uint32
+    FrameMbsOnlyFlag uint8
+    FrameCropLeftOffset uint32
+    FrameCropRightOffset uint32
+    FrameCropTopOffset uint32
+    FrameCropBottomOffset uint32
+
+    // The VUI, E.1.1 VUI parameters syntax, page 387.
+    VuiParametersPresentFlag uint8
+    AspectRatioIdc uint8
+    SarWidth uint16
+    SarHeight uint16
+    VideoFullRangeFlag uint8
+    ColourDescriptionPresentFlag uint8
+    ColourPrimaries uint8
+    TransferCharacteristics uint8
+    MatrixCoefficients uint8
+    TimingInfoPresentFlag uint8
+    NumUnitsInTick uint32
+    TimeScale uint32
+    FixedFrameRateFlag uint8
+}
+
+// Decode the SPS from the NALU, which includes the NALU header.
+func NewMp4AvcSps(nalu []uint8) (v *Mp4AvcSps, err error) {
+    if len(nalu) < 4 {
+        return nil, fmt.Errorf("sps requires 4 bytes, actual %v", len(nalu))
+    }
+    if nalu[0] & 0x1f != SrsAvcNaluTypeSPS {
+        return nil, fmt.Errorf("invalid nalu type %v for sps", nalu[0] & 0x1f)
+    }
+
+    v = &Mp4AvcSps{
+        ProfileIdc: nalu[1],
+        ConstraintFlags: nalu[2],
+        LevelIdc: nalu[3],
+        // The 4:2:0 and 8 bits, when not present.
+        ChromaFormatIdc: 1,
+    }
+    if err = v.decode(newBitReader(nalu2rbsp(nalu[4:]))); err != nil {
+        return nil, err
+    }
+    return
+}
+
+func (v *Mp4AvcSps) decode(br *bitReader) (err error) {
+    if v.SeqParameterSetId, err = br.readUE(); err != nil {
+        return
+    }
+
+    switch v.ProfileIdc {
+    case 100, 110, 122, 244, 44, 83, 86, 118, 128, 138, 139, 134, 135:
+        if v.ChromaFormatIdc, err = br.readUE(); err != nil {
+            return
+        }
+        if v.ChromaFormatIdc == 3 {
+            if v.SeparateColourPlaneFlag, err = br.read8(1); err != nil {
+                return
+            }
+        }
+        if v.BitDepthLumaMinus8, err = br.readUE(); err != nil {
+            return
+        }
+        if v.BitDepthChromaMinus8, err = br.readUE(); err != nil {
+            return
+        }
+        // The qpprime_y_zero_transform_bypass_flag.
+        if _, err = br.read(1); err != nil {
+            return
+        }
+
+        var seqScalingMatrixPresentFlag uint8
+        if seqScalingMatrixPresentFlag, err = br.read8(1); err != nil {
+            return
+        }
+        if seqScalingMatrixPresentFlag != 0 {
+            nbLists := 8
+            if v.ChromaFormatIdc == 3 {
+                nbLists = 12
+            }
+            for i := 0; i < nbLists; i++ {
+                var present uint8
+                if present, err = br.read8(1); err != nil {
+                    return
+                }
+                if present == 0 {
+                    continue
+                }
+                size := 16
+                if i >= 6 {
+                    size = 64
+                }
+                if err = skipScalingList(br, size); err != nil {
+                    return
+                }
+            }
+        }
+    }
+
+    if v.Log2MaxFrameNumMinus4, err = br.readUE(); err != nil {
+        return
+    }
+    if v.PicOrderCntType, err = br.readUE(); err != nil {
+        return
+    }
+    if v.PicOrderCntType == 0 {
+        // The log2_max_pic_order_cnt_lsb_minus4.
+        if _, err = br.readUE(); err != nil {
+            return
+        }
+    } else if v.PicOrderCntType == 1 {
+        // The delta_pic_order_always_zero_flag, offset_for_non_ref_pic, offset_for_top_to_bottom_field.
+        if _, err = br.read(1); err != nil {
+            return
+        }
+        if _, err = br.readSE(); err != nil {
+            return
+        }
+        if _, err = br.readSE(); err != nil {
+            return
+        }
+        var nbCycle uint32
+        if nbCycle, err = br.readUE(); err != nil {
+            return
+        }
+        for i := 0; i < int(nbCycle); i++ {
+            if _, err = br.readSE(); err != nil {
+                return
+            }
+        }
+    }
+
+    if v.MaxNumRefFrames, err = br.readUE(); err != nil {
+        return
+    }
+    // The gaps_in_frame_num_value_allowed_flag.
+    if _, err = br.read(1); err != nil {
+        return
+    }
+    if v.PicWidthInMbsMinus1, err = br.readUE(); err != nil {
+        return
+    }
+    if v.PicHeightInMapUnitsMinus1, err = br.readUE(); err != nil {
+        return
+    }
+    if v.FrameMbsOnlyFlag, err = br.read8(1); err != nil {
+        return
+    }
+    if v.FrameMbsOnlyFlag == 0 {
+        // The mb_adaptive_frame_field_flag.
+        if _, err = br.read(1); err != nil {
+            return
+        }
+    }
+    // The direct_8x8_inference_flag.
+    if _, err = br.read(1); err != nil {
+        return
+    }
+
+    var frameCroppingFlag uint8
+    if frameCroppingFlag, err = br.read8(1); err != nil {
+        return
+    }
+    if frameCroppingFlag != 0 {
+        for _, offset := range []*uint32{&v.FrameCropLeftOffset, &v.FrameCropRightOffset, &v.FrameCropTopOffset, &v.FrameCropBottomOffset} {
+            if *offset, err = br.readUE(); err != nil {
+                return
+            }
+        }
+    }
+
+    // The crop window must be in the coded picture, see the frame_crop_left_offset of 7.4.2.1.1.
+    width, height := v.codedSize()
+    cropUnitX, cropUnitY := v.cropUnit()
+    if cropUnitX * (int64(v.FrameCropLeftOffset) + int64(v.FrameCropRightOffset)) >= width ||
+        cropUnitY * (int64(v.FrameCropTopOffset) + int64(v.FrameCropBottomOffset)) >= height {
+        return fmt.Errorf("crop window %v,%v,%v,%v overflow the coded picture %vx%v", v.FrameCropLeftOffset,
+            v.FrameCropRightOffset, v.FrameCropTopOffset, v.FrameCropBottomOffset, width, height)
+    }
+
+    if v.VuiParametersPresentFlag, err = br.read8(1); err != nil {
+        return
+    }
+    if v.VuiParametersPresentFlag != 0 {
+        return v.decodeVui(br)
+    }
+    return
+}
+
+func (v *Mp4AvcSps) decodeVui(br *bitReader) (err error) {
+    var flag uint8
+    if flag, err = br.read8(1); err != nil {
+        return
+    }
+    if flag != 0 {
+        if v.AspectRatioIdc, err = br.read8(8); err != nil {
+            return
+        }
+        if v.AspectRatioIdc == SRS_AVC_ASPECT_RATIO_EXTENDED_SAR {
+            var u uint32
+            if u, err = br.read(16); err != nil {
+                return
+            }
+            v.SarWidth = uint16(u)
+            if u, err = br.read(16); err != nil {
+                return
+            }
+            v.SarHeight = uint16(u)
+        }
+    }
+
+    // The overscan_info_present_flag and overscan_appropriate_flag.
+    if flag, err = br.read8(1); err != nil {
+        return
+    }
+    if flag != 0 {
+        if _, err = br.read(1); err != nil {
+            return
+        }
+    }
+
+    // The video_signal_type_present_flag, with video_format.
+    if flag, err = br.read8(1); err != nil {
+        return
+    }
+    if flag != 0 {
+        if _, err = br.read(3); err != nil {
+            return
+        }
+        if v.VideoFullRangeFlag, err = br.read8(1); err != nil {
+            return
+        }
+        if v.ColourDescriptionPresentFlag, err = br.read8(1); err != nil {
+            return
+        }
+        if v.ColourDescriptionPresentFlag != 0 {
+            for _, field := range []*uint8{&v.ColourPrimaries, &v.TransferCharacteristics, &v.MatrixCoefficients} {
+                if *field, err = br.read8(8); err != nil {
+                    return
+                }
+            }
+        }
+    }
+
+    // The chroma_loc_info_present_flag, with the chroma sample locations.
+    if flag, err = br.read8(1); err != nil {
+        return
+    }
+    if flag != 0 {
+        if _, err = br.readUE(); err != nil {
+            return
+        }
+        if _, err = br.readUE(); err != nil {
+            return
+        }
+    }
+
+    if v.TimingInfoPresentFlag, err = br.read8(1); err != nil {
+        return
+    }
+    if v.TimingInfoPresentFlag != 0 {
+        if v.NumUnitsInTick, err = br.read(32); err != nil {
+            return
+        }
+        if v.TimeScale, err = br.read(32); err != nil {
+            return
+        }
+        if v.FixedFrameRateFlag, err = br.read8(1); err != nil {
+            return
+        }
+    }
+    return
+}
+
+// Skip the scaling_list, 7.3.2.1.1.1 Scaling list syntax.
+func skipScalingList(br *bitReader, size int) (err error) {
+    lastScale, nextScale := int32(8), int32(8)
+    for j := 0; j < size; j++ {
+        if nextScale != 0 {
+            var deltaScale int32
+            if deltaScale, err = br.readSE(); err != nil {
+                return
+            }
+            nextScale = (lastScale + deltaScale + 256) % 256
+        }
+        if nextScale != 0 {
+            lastScale = nextScale
+        }
+    }
+    return
+}
+
+// Get the width in pixels, with the cropping, see the frame_crop_left_offset of 7.4.2.1.1.
+func (v *Mp4AvcSps) Width() int {
+    width, _ := v.codedSize()
+    cropUnitX, _ := v.cropUnit()
+    return int(width - cropUnitX * (int64(v.FrameCropLeftOffset) + int64(v.FrameCropRightOffset)))
+}
+
+// Get the height in pixels, with the cropping, the field pictures make a frame when not frame_mbs_only.
+func (v *Mp4AvcSps) Height() int {
+    _, height := v.codedSize()
+    _, cropUnitY := v.cropUnit()
+    return int(height - cropUnitY * (int64(v.FrameCropTopOffset) + int64(v.FrameCropBottomOffset)))
+}
+
+// Get the size of the coded picture in luma samples, before cropping.
+func (v *Mp4AvcSps) codedSize() (width, height int64) {
+    width = (int64(v.PicWidthInMbsMinus1) + 1) * 16
+    height = (2 - int64(v.FrameMbsOnlyFlag)) * (int64(v.PicHeightInMapUnitsMinus1) + 1) * 16
+    return
+}
+
+// Get the CropUnitX and CropUnitY, by the chroma format and frame_mbs_only, see 7.4.2.1.1.
+func (v *Mp4AvcSps) cropUnit() (x, y int64) {
+    x, y = 1, 2 - int64(v.FrameMbsOnlyFlag)
+    if v.chromaArrayType() == 1 || v.chromaArrayType() == 2 {
+        x = 2
+    }
+    if v.chromaArrayType() == 1 {
+        y *= 2
+    }
+    return
+}
+
+func (v *Mp4AvcSps) chromaArrayType() uint32 {
+    if v.SeparateColourPlaneFlag != 0 {
+        return 0
+    }
+    return v.ChromaFormatIdc
+}
+
+// Get the frame rate by the VUI timing, 0 if not present, which is the field rate halved.
+func (v *Mp4AvcSps) FrameRate() float64 {
+    if v.TimingInfoPresentFlag == 0 || v.NumUnitsInTick == 0 {
+        return 0
+    }
+    return float64(v.TimeScale) / float64(2 * v.NumUnitsInTick)
+}
+
+// Get the sample aspect ratio, Table E-1, 0:0 for unspecified.
+func (v *Mp4AvcSps) SampleAspectRatio() (width, height uint16) {
+    if v.AspectRatioIdc == SRS_AVC_ASPECT_RATIO_EXTENDED_SAR {
+        return v.SarWidth, v.SarHeight
+    }
+    sars := [][2]uint16{{0, 0}, {1, 1}, {12, 11}, {10, 11}, {16, 11}, {40, 33}, {24, 11}, {20, 11}, {32, 11},
+        {80, 33}, {18, 11}, {15, 11}, {64, 33}, {160, 99}, {4, 3}, {3, 2}, {2, 1}}
+    if int(v.AspectRatioIdc) < len(sars) {
+        return sars[v.AspectRatioIdc][0], sars[v.AspectRatioIdc][1]
+    }
+    return 0, 0
+}
+
+// Get the level in string, for example, 3.1 for SrsAvcLevel_31, empty for the unknown level.
+func (v *Mp4AvcSps) Level() string {
+    return AvcLevel(v.LevelIdc)
+}
+
+// Get the level in string, for example, 3.1 for SrsAvcLevel_31.
+func AvcLevel(level uint8) string {
+    switch level {
+    case SrsAvcLevel_1, SrsAvcLevel_11, SrsAvcLevel_12, SrsAvcLevel_13, SrsAvcLevel_2, SrsAvcLevel_21, SrsAvcLevel_22,
+        SrsAvcLevel_3, SrsAvcLevel_31, SrsAvcLevel_32, SrsAvcLevel_4, SrsAvcLevel_41, SrsAvcLevel_5, SrsAvcLevel_51, SrsAvcLevel_52, SrsAvcLevel_6, SrsAvcLevel_61, SrsAvcLevel_62:
+        return fmt.Sprintf("%v.%v", level / 10, level % 10)
+    }
+    return ""
+}
+
+// Get the name of profile, for example, High for SrsAvcProfileHigh.
+func AvcProfile(profile uint8) string {
+    switch profile {
+    case SrsAvcProfileBaseline:
+        return "Baseline"
+    case SrsAvcProfileMain:
+        return "Main"
+    case SrsAvcProfileExtended:
+        return "Extended"
+    case SrsAvcProfileHigh:
+        return "High"
+    case SrsAvcProfileHigh10:
+        return "High 10"
+    case SrsAvcProfileHigh422:
+        return "High 4:2:2"
+    case SrsAvcProfileHigh444, SrsAvcProfileHigh444Predictive:
+        return "High 4:4:4"
+    }
+    return fmt.Sprintf("Profile %v", profile)
+}
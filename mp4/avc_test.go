@@ -0,0 +1,154 @@
+package mp4
+
+import (
+    "testing"
+)
+
+// The SPS of High 4:4:4 Predictive, which x264 writes for 4:4:4 and lossless, 1280x720 of 10 bits with
+// scaling lists, the poc type 1 and the extended SAR of 4:3.
+var testSps444 = []byte{0x67, 0xf4, 0x00, 0x33, 0x44, 0x36, 0xc2, 0x1f, 0xff, 0xc1, 0x08, 0x7f, 0xff, 0xff, 0xff, 0xff,
+    0xff, 0xff, 0xff, 0x03, 0x42, 0xa6, 0x42, 0x94, 0x05, 0x00, 0x5b, 0xbf, 0xf0, 0x00, 0x40, 0x00, 0x30, 0x10}
+
+func TestAvcSps(t *testing.T) {
+    cases := []struct {
+        name string
+        sps []byte
+        profile, level uint8
+        width, height int
+        fps float64
+        sar [2]uint16
+        chroma, bitDepth uint32
+    }{
+        {"high", testSps, SrsAvcProfileHigh, SrsAvcLevel_31, 1920, 1080, 30000.0 / 1001, [2]uint16{1, 1}, 1, 8},
+        // The 640x368 cropped to 640x360, the poc type 2 and no VUI.
+        {"baseline", []byte{0x67, 0x42, 0xc0, 0x1e, 0xda, 0x02, 0x80, 0xbf, 0xe5, 0x40},
+            SrsAvcProfileBaseline, SrsAvcLevel_3, 640, 360, 0, [2]uint16{0, 0}, 1, 8},
+        // The 1920x1080 interlaced of 34 map units of field pair, the VUI of full range BT.709 and 1001/60000.
+        {"main interlaced", []byte{0x67, 0x4d, 0x40, 0x28, 0xec, 0xa0, 0x3c, 0x02, 0x27, 0xef, 0x01, 0x6e, 0x02, 0x02, 0x02,
+            0x80, 0x00, 0x01, 0xf4, 0x80, 0x00, 0x75, 0x30, 0x42},
+            SrsAvcProfileMain, SrsAvcLevel_4, 1920, 1080, 30000.0 / 1001, [2]uint16{1, 1}, 1, 8},
+        {"high 4:4:4", testSps444, SrsAvcProfileHigh444Predictive, SrsAvcLevel_51, 1280, 720, 0, [2]uint16{4, 3}, 3, 10},
+    }
+    for _, c := range cases {
+        sps, err := NewMp4AvcSps(c.sps)
+        if err != nil {
+            t.Errorf("%v: %v", c.name, err)
+            continue
+        }
+        if sps.ProfileIdc != c.profile || sps.LevelIdc != c.level || sps.Width() != c.width || sps.Height() != c.height {
+            t.Errorf("%v: profile %v, level %v, %vx%v", c.name, sps.ProfileIdc, sps.LevelIdc, sps.Width(), sps.Height())
+        }
+        if fps := sps.FrameRate(); fps != c.fps {
+            t.Errorf("%v: frame rate %v, expect %v", c.name, fps, c.fps)
+        }
+        if w, h := sps.SampleAspectRatio(); [2]uint16{w, h} != c.sar {
+            t.Errorf("%v: sar %v:%v, expect %v", c.name, w, h, c.sar)
+        }
+        if sps.ChromaFormatIdc != c.chroma || sps.BitDepthLumaMinus8 + 8 != c.bitDepth || sps.BitDepthChromaMinus8 + 8 != c.bitDepth {
+            t.Errorf("%v: chroma %v, bit depth %v/%v", c.name, sps.ChromaFormatIdc, sps.BitDepthLumaMinus8 + 8, sps.BitDepthChromaMinus8 + 8)
+        }
+    }
+
+    // The VUI of the interlaced.
+    sps, _ := NewMp4AvcSps(cases[2].sps)
+    if sps.FrameMbsOnlyFlag != 0 || sps.VideoFullRangeFlag != 1 || sps.ColourPrimaries != 1 || sps.TransferCharacteristics != 1 ||
+        sps.MatrixCoefficients != 1 || sps.FixedFrameRateFlag != 1 || sps.MaxNumRefFrames != 4 {
+        t.Errorf("interlaced sps %+v", sps)
+    }
+
+    // The SPS from the avcC of track.
+    video, _ := newAvTracks()
+    avcc, err := parseTracks(t, buildFile(false, video))[0].Avcc()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if sps, err := avcc.Sps(); err != nil || sps.Width() != 1920 || sps.Height() != 1080 || sps.Level() != "3.1" || AvcProfile(sps.ProfileIdc) != "High" {
+        t.Errorf("avcC sps %+v, err is %v", sps, err)
+    }
+
+    for name, nalu := range map[string][]byte{
+        "pps": testPps,
+        "short": testSps[:3],
+        "truncated": testSps[:8],
+    } {
+        if _, err := NewMp4AvcSps(nalu); err == nil {
+            t.Errorf("%v: should fail", name)
+        }
+    }
+}
+
+func TestAvcSpsCrop(t *testing.T) {
+    // The baseline 640x368 cropped at bottom by 366 rows, the smallest picture of 640x2.
+    sps, err := NewMp4AvcSps([]byte{0x67, 0x42, 0xc0, 0x1e, 0xda, 0x02, 0x80, 0xbf, 0xe0, 0x2e, 0x10})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if sps.Width() != 640 || sps.Height() != 2 {
+        t.Errorf("%vx%v, expect 640x2", sps.Width(), sps.Height())
+    }
+
+    // The crop windows which remove the whole picture, or overflow it.
+    cases := []struct {
+        name string
+        sps []byte
+    }{
+        {"bottom 368", []byte{0x67, 0x42, 0xc0, 0x1e, 0xda, 0x02, 0x80, 0xbf, 0xe0, 0x2e, 0x50}},
+        {"left and right 320", []byte{0x67, 0x42, 0xc0, 0x1e, 0xda, 0x02, 0x80, 0xbf, 0x01, 0x42, 0x02, 0x87, 0x40}},
+        {"bottom 0xfffffffe", []byte{0x67, 0x42, 0xc0, 0x1e, 0xda, 0x02, 0x80, 0xbf, 0xe0, 0x00, 0x00, 0x03, 0x00, 0x3f, 0xff, 0xff,
+            0xff, 0xd0}},
+    }
+    for _, c := range cases {
+        if sps, err := NewMp4AvcSps(c.sps); err == nil {
+            t.Errorf("%v: decoded as %vx%v", c.name, sps.Width(), sps.Height())
+        }
+    }
+}
+
+func TestAvccExtension(t *testing.T) {
+    // The avcC of sps, pps and the extension of chroma format, bit depths and no SPS extension.
+    avcc := func(sps []byte, ext ...byte) []byte {
+        return box("avcC", []byte{1, sps[1], sps[2], sps[3], 0xff, 0xe1}, be(uint16(len(sps))), sps,
+            []byte{1}, be(uint16(len(testPps))), testPps, ext)
+    }
+    cases := []struct {
+        name string
+        avcc []byte
+        extension bool
+        chroma, luma, chromaDepth uint8
+    }{
+        {"high", avcc(testSps, 0xfd, 0xf8, 0xf8, 0), true, 1, 0, 0},
+        {"high 4:4:4 predictive", avcc(testSps444, 0xff, 0xfa, 0xfa, 0), true, 3, 2, 2},
+        // The extension is optional, which is missing in some encoders.
+        {"high without extension", avcc(testSps), false, 0, 0, 0},
+        // The Baseline, Main and Extended profiles have no extension, the bytes are ignored.
+        {"baseline", avcc([]byte{0x67, 0x42, 0xc0, 0x1e, 0xda, 0x02, 0x80, 0xbf, 0xe5, 0x40}, 0xfd, 0xf8, 0xf8, 0), false, 0, 0, 0},
+    }
+    for _, c := range cases {
+        video := newTestTrack(1, "vide", visualEntry("avc1", 1280, 720, c.avcc), 3)
+        v, err := parseTracks(t, buildFile(false, video))[0].Avcc()
+        if err != nil {
+            t.Fatalf("%v: %v", c.name, err)
+        }
+        if v.HighProfileExtension != c.extension || v.ChromaFormat != c.chroma || v.BitDepthLumaMinus8 != c.luma ||
+            v.BitDepthChromaMinus8 != c.chromaDepth {
+            t.Errorf("%v: extension %v, chroma %v, bit depth %v/%v", c.name, v.HighProfileExtension, v.ChromaFormat,
+                v.BitDepthLumaMinus8 + 8, v.BitDepthChromaMinus8 + 8)
+        }
+        if fields := v.Fields(); c.extension && (fields["chroma_format"] != c.chroma || fields["bit_depth_luma"] != c.luma + 8) {
+            t.Errorf("%v: fields %v", c.name, fields)
+        }
+    }
+}
+
+func TestAvcLevel(t *testing.T) {
+    for level, expect := range map[uint8]string{10: "1.0", 11: "1.1", 31: "3.1", 40: "4.0", 51: "5.1", 62: "6.2", 9: "", 33: ""} {
+        if v := AvcLevel(level); v != expect {
+            t.Errorf("level %v is %v, expect %v", level, v, expect)
+        }
+    }
+    for profile, expect := range map[uint8]string{66: "Baseline", 77: "Main", 100: "High", 110: "High 10", 244: "High 4:4:4", 1: "Profile 1"} {
+        if v := AvcProfile(profile); v != expect {
+            t.Errorf("profile %v is %v, expect %v", profile, v, expect)
+        }
+    }
+}
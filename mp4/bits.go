@@ -0,0 +1,96 @@
+package mp4
+
+import (
+    "fmt"
+)
+
+// The reader of bits in big-endian, for the bit fields of codec configurations.
+type bitReader struct {
+    data []uint8
+    // The position in bits.
+    pos int
+}
+
+func newBitReader(data []uint8) *bitReader {
+    return &bitReader{data: data}
+}
+
+// Get the number of bits left.
+func (v *bitReader) left() int {
+    return len(v.data) * 8 - v.pos
+}
+
+// Read n bits, at most 32.
+func (v *bitReader) read(n int) (value uint32, err error) {
+    if n > 32 || n > v.left() {
+        return 0, fmt.Errorf("read %v bits overflow, left=%v", n, v.left())
+    }
+    for i := 0; i < n; i++ {
+        bit := (v.data[v.pos / 8] >> uint(7 - v.pos % 8)) & 0x01
+        value = value << 1 | uint32(bit)
+        v.pos++
+    }
+    return
+}
+
+// Read n bits as uint8, at most 8.
+func (v *bitReader) read8(n int) (value uint8, err error) {
+    var u uint32
+    u, err = v.read(n)
+    return uint8(u), err
+}
+
+// Read the unsigned Exp-Golomb code ue(v), see 9.1 Parsing process for Exp-Golomb codes,
+// ISO_IEC_14496-10-AVC-2012.pdf, page 209.
+func (v *bitReader) readUE() (value uint32, err error) {
+    var leadingZeroBits int
+    for {
+        var bit uint32
+        if bit, err = v.read(1); err != nil {
+            return
+        }
+        if bit != 0 {
+            break
+        }
+        if leadingZeroBits++; leadingZeroBits > 31 {
+            return 0, fmt.Errorf("ue overflow, leading zero bits %v", leadingZeroBits)
+        }
+    }
+
+    var suffix uint32
+    if suffix, err = v.read(leadingZeroBits); err != nil {
+        return
+    }
+    return (1 << uint(leadingZeroBits)) - 1 + suffix, nil
+}
+
+// Read the signed Exp-Golomb code se(v), which is mapped from ue(v), Table 9-3.
+func (v *bitReader) readSE() (value int32, err error) {
+    var u uint32
+    if u, err = v.readUE(); err != nil {
+        return
+    }
+    if u & 0x01 != 0 {
+        return int32((u + 1) / 2), nil
+    }
+    return -int32(u / 2), nil
+}
+
+// Remove the emulation prevention bytes, the 0x03 of 0x000003, to get the RBSP from NALU.
+func nalu2rbsp(nalu []uint8) []uint8 {
+    rbsp := make([]uint8, 0, len(nalu))
+    var zeros int
+    for _, b := range nalu {
+        if zeros >= 2 && b == 0x03 {
+            zeros = 0
+            continue
+        }
+        rbsp = append(rbsp, b)
+        if b == 0 {
+            zeros++
+        } else {
+            zeros = 0
+        }
+    }
+    return rbsp
+}
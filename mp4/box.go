@@ -0,0 +1,5389 @@
+package mp4
+
+import (
+    "fmt"
+    "io"
+    ol "github.com/ossrs/go-oryx-lib/logger"
+    "encoding/binary"
+    "reflect"
+    "bytes"
+    "strings"
+    "math"
+)
+
+type Box interface {
+    Basic() *Mp4Box
+    NbHeader() int
+    DecodeHeader(r io.Reader) (err error)
+    EncodeHeader(w io.Writer) (err error)
+}
+
+// The box whose payload is skipped when decoding, for example, mdat, so the payload is copied
+// from the source file when encoding, see Encode.
+type PayloadBox interface {
+    Box
+    Payload() (offset int, size int)
+}
+
+// The chunk offsets of stco or co64, see Mp4ChunkOffsetBox and Mp4ChunkLargeOffsetBox.
+type ChunkOffsetBox interface {
+    Box
+    // Get the number of chunks.
+    NbChunks() int
+    // Get the offset of chunk, the index starts from 0.
+    ChunkOffset(index int) uint64
+}
+
+// The sample sizes of stsz or stz2, see Mp4SampleSizeBox and Mp4CompactSampleSizeBox.
+type SampleSizeBox interface {
+    Box
+    // Get the number of samples.
+    NbSamples() int
+    // Get the size of sample, the index starts from 0.
+    EntrySize(index int) uint32
+}
+
+// The sample entry of stsd, for example, avc1 and mp4a, see Mp4SampleEntry.
+type SampleEntryBox interface {
+    Box
+    Entry() *Mp4SampleEntry
+}
+
+// The box with version and flags, see Mp4FullBox.
+type FullBox interface {
+    Box
+    Full() *Mp4FullBox
+}
+
+type Mp4Box struct {
+    // The size is the entire size of the box, including the size and type header, fields,
+    // and all contained boxes. This facilitates general parsing of the file.
+    //
+    // if size is 1 then the actual size is in the field largesize;
+    // if size is 0, then this box is the last one in the file, and its contents
+    // extend to the end of the file (normally only used for a Media Data Box)
+    // @remark For size 0, the LargeSize is resolved to the size to the end of file or the container.
+    SmallSize uint32
+    LargeSize uint64
+
+    // For box 'uuid'.
+    UserType  [16]uint8
+
+    // The position at buffer to start demux the box.
+    StartPos  int
+
+    // identifies the box type; standard boxes use a compact type, which is normally four printable
+    // characters, to permit ease of identification, and is shown so in the boxes below. User extensions use
+    // an extended type; in this case, the type field is set to ‘uuid’.
+    BoxType   uint32
+
+    Boxes     []Box
+    UsedSize  uint64
+}
+
+func NewMp4Box() *Mp4Box {
+    v := &Mp4Box{}
+    v.BoxType = SrsMp4BoxTypeForbidden
+    v.Boxes = []Box{}
+    v.UserType = [16]uint8{}
+    return v
+}
+
+// Get the size of box, whatever small or large size.
+func (v *Mp4Box) Size() uint64 {
+    if v.SmallSize == SRS_MP4_USE_LARGE_SIZE || v.SmallSize == SRS_MP4_EOF_SIZE {
+        return v.LargeSize
+    }
+    return uint64(v.SmallSize)
+}
+
+func (v *Mp4Box) left() uint64 {
+    ol.I(nil, "left:", v.Size(), v.UsedSize)
+    return v.Size() - v.UsedSize
+}
+
+// Get the contained box of specific type.
+// @return The first matched box.
+func (v *Mp4Box) Get(bt uint32) (Box, error) {
+    for _, box := range v.Boxes {
+        if box.Basic().BoxType == bt {
+            return box, nil
+        }
+    }
+    return nil, fmt.Errorf("can't find bt:%v in boxes", bt)
+}
+
+
+// Remove the contained box of specified type.
+// @return The removed count.
+func (v *Mp4Box) Remove(bt uint32) (nbRemoved int) {
+    for k, box := range v.Boxes {
+        if box.Basic().BoxType == bt {
+            v.Boxes = append(v.Boxes[:k], v.Boxes[k+1:]...)
+            nbRemoved ++
+        }
+    }
+    return
+}
+
+func (v *Mp4Box) NbHeader() int {
+    size := 8
+    if v.SmallSize == SRS_MP4_USE_LARGE_SIZE {
+        size += 8
+    }
+    if v.BoxType == SrsMp4BoxTypeUUID {
+        size += 16
+    }
+    return size
+}
+
+func (v *Mp4Box) DecodeHeader(r io.Reader) (err error) {
+    return
+}
+
+// Encode the size, type, largesize and usertype, the size must be updated, see Encode.
+func (v *Mp4Box) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Write(w, v.SmallSize, v.BoxType); err != nil {
+        ol.E(nil, fmt.Sprintf("write size and type failed, err is %v", err))
+        return
+    }
+    if v.SmallSize == SRS_MP4_USE_LARGE_SIZE {
+        if err = v.Write(w, v.LargeSize); err != nil {
+            ol.E(nil, fmt.Sprintf("write large size failed, err is %v", err))
+            return
+        }
+    }
+    if v.BoxType == SrsMp4BoxTypeUUID {
+        if err = v.Write(w, v.UserType[:]); err != nil {
+            ol.E(nil, fmt.Sprintf("write user type failed, err is %v", err))
+            return
+        }
+    }
+    return
+}
+
+func (v *Mp4Box) discovery(r io.Reader) (box Box, err error) {
+    v.UsedSize = 0
+
+    // Discovery the size and type.
+    var largeSize uint64
+    var smallSize uint32
+
+    if err = v.Read(r, &smallSize); err != nil {
+        ol.E(nil, fmt.Sprintf("read small size failed, err is %v", err))
+        return
+    }
+
+    var bt uint32
+    if err = v.Read(r, &bt); err != nil {
+        ol.E(nil, fmt.Sprintf("read type failed, err is %v", err))
+        return
+    }
+
+    if smallSize == SRS_MP4_USE_LARGE_SIZE {
+        if err = v.Read(r, &largeSize); err != nil {
+            ol.E(nil, fmt.Sprintf("read large size failed, err is %v", err))
+            return
+        }
+    }
+
+    userType := make([]uint8, 16)
+    if bt == SrsMp4BoxTypeUUID {
+        if err = v.Read(r, userType); err != nil {
+            ol.E(nil, fmt.Sprintf("read user type failed, err is %v", err))
+            return
+        }
+    }
+
+    switch bt {
+    case SrsMp4BoxTypeFTYP:
+        box = NewMp4FileTypeBox()
+    case SrsMp4BoxTypeMOOV:
+        box = NewMp4MovieBox()
+    case SrsMp4BoxTypeMVHD:
+        box = NewMp4MovieHeaderBox()
+    case SrsMp4BoxTypeTRAK:
+        box = &Mp4TrackBox{}
+    case SrsMp4BoxTypeTKHD:
+        box = NewMp4TrackHeaderBox()
+    case SrsMp4BoxTypeMDIA:
+        box = &Mp4MediaBox{}
+    case SrsMp4BoxTypeMDHD:
+        box = &Mp4MediaHeaderBox{}
+    case SrsMp4BoxTypeHDLR:
+        box = NewMp4HandlerReferenceBox()
+    case SrsMp4BoxTypeMINF:
+        box = &Mp4MediaInformationBox{}
+    case SrsMp4BoxTypeVMHD:
+        box = NewMp4VideoMediaHeaderBox()
+    case SrsMp4BoxTypeSMHD:
+        box = NewMp4SoundMediaHeaderBox()
+    case SrsMp4BoxTypeHMHD:
+        box = NewMp4HintMediaHeaderBox()
+    case SrsMp4BoxTypeSTHD:
+        box = NewMp4SubtitleMediaHeaderBox()
+    case SrsMp4BoxTypeNMHD:
+        box = NewMp4NullMediaHeaderBox()
+    case SrsMp4BoxTypeDINF:
+        box = &Mp4DataInformationBox{}
+    case SrsMp4BoxTypeDREF:
+        box = NewMp4DataReferenceBox()
+    case SrsMp4BoxTypeURL:
+        box = NewMp4DataEntryUrlBox()
+    case SrsMp4BoxTypeURN:
+        box = &Mp4DataEntryUrnBox{}
+    case SrsMp4BoxTypeEDTS:
+        box = &Mp4EditBox{}
+    case SrsMp4BoxTypeELST:
+        box = NewMp4EditListBox()
+    case SrsMp4BoxTypeSTBL:
+        box = &Mp4SampleTableBox{}
+
+    case SrsMp4BoxTypeAVC1:
+        box = NewMp4VisualSampleEntry()
+    case SrsMp4BoxTypeAVCC:
+        box = &Mp4AvccBox{}
+    case SrsMp4BoxTypeHVC1, SrsMp4BoxTypeHEV1:
+        box = NewMp4VisualSampleEntry()
+    case SrsMp4BoxTypeHVCC:
+        box = &Mp4HvccBox{}
+    case SrsMp4BoxTypeAV01:
+        box = NewMp4VisualSampleEntry()
+    case SrsMp4BoxTypeAV1C:
+        box = &Mp4Av1cBox{}
+    case SrsMp4BoxTypeVP09, SrsMp4BoxTypeVP08:
+        box = NewMp4VisualSampleEntry()
+    case SrsMp4BoxTypeVPCC:
+        box = &Mp4VpccBox{}
+    case SrsMp4BoxTypeMP4A:
+        box = &Mp4AudioSampleEntry{}
+    case SrsMp4BoxTypeOPUS:
+        box = &Mp4AudioSampleEntry{}
+    case SrsMp4BoxTypeDOPS:
+        box = &Mp4OpusSpecificBox{}
+    case SrsMp4BoxTypeAC3, SrsMp4BoxTypeEC3:
+        box = &Mp4AudioSampleEntry{}
+    case SrsMp4BoxTypeDAC3:
+        box = &Mp4Ac3SpecificBox{}
+    case SrsMp4BoxTypeDEC3:
+        box = &Mp4Ec3SpecificBox{}
+    case SrsMp4BoxTypeFLAC, SrsMp4BoxTypeIPCM, SrsMp4BoxTypeFPCM, SrsMp4BoxTypeLPCM, SrsMp4BoxTypeTWOS, SrsMp4BoxTypeSOWT:
+        box = &Mp4AudioSampleEntry{}
+    case SrsMp4BoxTypeDFLA:
+        box = &Mp4FlacSpecificBox{}
+    case SrsMp4BoxTypePCMC:
+        box = &Mp4PcmConfigurationBox{}
+    case SrsMp4BoxTypeESDS:
+        box = NewMp4EsdsBox()
+
+    case SrsMp4BoxTypeSTSD:
+        box = NewMp4SampleDescritionBox()
+    case SrsMp4BoxTypeSTTS:
+        box = NewMp4DecodingTime2SampleBox()
+    case SrsMp4BoxTypeCTTS:
+        box = NewMp4CompositionTime2SampleBox()
+    case SrsMp4BoxTypeSTSS:
+        box = NewMp4SyncSampleBox()
+    case SrsMp4BoxTypeSTSC:
+        box = NewMp4Sample2ChunkBox()
+    case SrsMp4BoxTypeSTSZ:
+        box = NewMp4SampleSizeBox()
+    case SrsMp4BoxTypeSTZ2:
+        box = NewMp4CompactSampleSizeBox()
+    case SrsMp4BoxTypeSTCO:
+        box = NewMp4ChunkOffsetBox()
+    case SrsMp4BoxTypeCO64:
+        box = NewMp4ChunkLargeOffsetBox()
+    case SrsMp4BoxTypeUDTA:
+        box = NewMp4UserDataBox()
+    case SrsMp4BoxTypeMDAT:
+        box = NewMp4MediaDataBox()
+    default:
+        box = NewMp4FreeSpaceBox()
+    }
+
+    box.Basic().BoxType = bt
+    box.Basic().SmallSize = smallSize
+    box.Basic().LargeSize = largeSize
+    copy(box.Basic().UserType[:], userType)
+    box.Basic().UsedSize = v.UsedSize
+
+    // The size must cover the header, or the left space overflows.
+    // The box to the end of file is resolved by the caller, who knows the size of file.
+    if smallSize != SRS_MP4_EOF_SIZE && box.Basic().Size() < v.UsedSize {
+        err = fmt.Errorf("invalid box size %v, bt=%x", box.Basic().Size(), bt)
+        ol.E(nil, err.Error())
+        return
+    }
+
+    ol.I(nil, fmt.Sprintf("discovery a new box:%v small size=%v, large size=%v, bt=%x", reflect.TypeOf(box), smallSize, largeSize, bt))
+    return
+}
+
+func (v *Mp4Box) DecodeBoxes(r io.Reader) (err error) {
+    // read left space
+    left := v.left()
+    pos := v.StartPos + int(v.UsedSize)
+    ol.T(nil, fmt.Sprintf("after decode header, left space:%v", left))
+    for {
+        if left <= 0 {
+            break
+        }
+
+        var box Box
+        if box, err = v.discovery(r); err != nil {
+            ol.E(nil, fmt.Sprintf("mp4 discovery contained box failed, err is %v", err))
+            return
+        }
+        box.Basic().StartPos = pos
+
+        // The box extends to the end of the container.
+        if box.Basic().SmallSize == SRS_MP4_EOF_SIZE {
+            box.Basic().LargeSize = left
+        }
+        if box.Basic().Size() > left {
+            err = fmt.Errorf("box size %v overflow the container, left=%v", box.Basic().Size(), left)
+            ol.E(nil, err.Error())
+            return
+        }
+
+        if err = box.DecodeHeader(r); err != nil {
+            ol.E(nil, fmt.Sprintf("mp4 decode contained box header failed, err is %v", err))
+            return
+        }
+        if err = box.Basic().DecodeBoxes(r); err != nil {
+            ol.E(nil, fmt.Sprintf("mp4 decode contained box boxes failed, err is %v", err))
+            return
+        }
+
+        ol.T(nil, fmt.Sprintf("box:%v decode boxes success, sub boxes=%v, box.sz=%v, left=%v %v.", reflect.TypeOf(box), len(box.Basic().Boxes), box.Basic().Size(), left, left - box.Basic().Size()))
+
+        v.Boxes = append(v.Boxes, box)
+
+        left -= box.Basic().Size()
+        pos += int(box.Basic().Size())
+    }
+    return
+}
+
+// Skip num bytes, by seeking when r is an io.Seeker, or by discarding with a bounded buffer,
+// so the payload of mdat is never read into memory.
+func (v *Mp4Box) Skip(r io.Reader, num uint64) (err error) {
+    if num <= 0 {
+        return
+    }
+
+    // The seek maybe fail, for example, the stdin of pipe, so fallback to discard.
+    if rs, ok := r.(io.Seeker); ok {
+        if _, err = rs.Seek(int64(num), io.SeekCurrent); err == nil {
+            v.UsedSize += num
+            ol.I(nil, fmt.Sprintf("skip %v bytes by seek", num))
+            return
+        }
+    }
+
+    var n int64
+    n, err = io.CopyN(io.Discard, r, int64(num))
+    v.UsedSize += uint64(n)
+    if err == io.EOF {
+        err = io.ErrUnexpectedEOF
+    }
+    if err != nil {
+        ol.E(nil, fmt.Sprintf("skip %v bytes failed, skipped=%v, err is %v", num, n, err))
+        return
+    }
+    ol.I(nil, fmt.Sprintf("skip %v bytes", num))
+    return
+}
+
+func (v *Mp4Box) Read(r io.Reader, data interface{}) (err error) {
+    if err = binary.Read(r, binary.BigEndian, data); err != nil {
+        return
+    }
+    v.UsedSize += uint64DataSize(data)
+    return
+}
+
+// Write the data in big-endian, stop at the first error.
+func (v *Mp4Box) Write(w io.Writer, data ...interface{}) (err error) {
+    for _, d := range data {
+        if err = binary.Write(w, binary.BigEndian, d); err != nil {
+            return
+        }
+    }
+    return
+}
+
+type Mp4FreeSpaceBox struct {
+    Mp4Box
+    // The position of the payload in file, which is skipped when decoding.
+    DataOffset int
+    needSkip int
+}
+
+func NewMp4FreeSpaceBox() *Mp4FreeSpaceBox {
+    v := &Mp4FreeSpaceBox{
+    }
+    return v
+}
+
+func (v *Mp4FreeSpaceBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4FreeSpaceBox) NbHeader() int {
+    return v.Mp4Box.NbHeader() + v.needSkip
+}
+
+func (v *Mp4FreeSpaceBox) DecodeHeader(r io.Reader) (err error) {
+    v.DataOffset = v.StartPos + int(v.UsedSize)
+    v.needSkip = int(v.left())
+    return v.Skip(r, v.left())
+}
+
+func (v *Mp4FreeSpaceBox) Payload() (offset int, size int) {
+    return v.DataOffset, v.needSkip
+}
+
+// ftyp box
+type Mp4FileTypeBox struct {
+    Mp4Box
+    MajorBrand uint32
+    MinorVersion uint32
+    CompatibleBrands []uint32
+}
+
+func NewMp4FileTypeBox() *Mp4FileTypeBox {
+    v := &Mp4FileTypeBox{
+        MajorBrand: SrsMp4BoxBrandForbidden,
+        MinorVersion: 0,
+        CompatibleBrands: []uint32{},
+    }
+    return v
+}
+
+func (v *Mp4FileTypeBox) setCompatibleBrands(b0, b1, b2, b3 uint32) {
+    v.CompatibleBrands = append(v.CompatibleBrands, []uint32{b0, b1, b2, b3}...)
+}
+
+func (v *Mp4FileTypeBox) DecodeHeader(r io.Reader) (err error) {
+    /*if err = v.Mp4Box.DecodeHeader(r); err != nil {
+        return err
+    }*/
+
+    ol.I(nil, fmt.Sprintf("decode ftyp box, usedSize=%v", v.UsedSize))
+    if err = v.Read(r, &v.MajorBrand); err != nil {
+        ol.E(nil, fmt.Sprintf("read major brand failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.MinorVersion); err != nil {
+        ol.E(nil, fmt.Sprintf("read minor version failed, err is %v", err))
+        return
+    }
+
+    // Compatible brands to the end of the box.
+    left := v.Mp4Box.left()
+    if (left > 0) {
+        for i := 0; i < int(left) / 4; i ++ {
+            var brand uint32
+            if err = v.Read(r, &brand); err != nil {
+                ol.E(nil, fmt.Sprintf("read brand failed, err is %v", err))
+                return
+            }
+            v.CompatibleBrands = append(v.CompatibleBrands, brand)
+        }
+    }
+    return
+}
+
+func (v *Mp4FileTypeBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4Box.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.MajorBrand, v.MinorVersion, v.CompatibleBrands); err != nil {
+        ol.E(nil, fmt.Sprintf("write ftyp failed, err is %v", err))
+        return
+    }
+    return
+}
+
+func (v *Mp4FileTypeBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4FileTypeBox) NbHeader() int {
+    return v.Mp4Box.NbHeader() + 8 + len(v.CompatibleBrands) * 4
+}
+
+/**
+ * 8.2.1 Movie Box (moov)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 30
+ * The metadata for a presentation is stored in the single Movie Box which occurs at the top-level of a file.
+ * Normally this box is close to the beginning or end of the file, though this is not required.
+ */
+// moov box
+type Mp4MovieBox struct {
+    Mp4Box
+}
+
+func NewMp4MovieBox() *Mp4MovieBox {
+    v := &Mp4MovieBox{}
+    return v
+}
+
+// Get the header of moov.
+func (v *Mp4MovieBox) Mvhd() (*Mp4MovieHeaderBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeMVHD); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4MovieHeaderBox), nil
+    }
+}
+
+func (v *Mp4MovieBox) Video() (*Mp4TrackBox, error) {
+    for _, box := range v.Boxes {
+        if tbox, ok := box.(*Mp4TrackBox); ok {
+            if tbox.TrackType() == SrsMp4TrackTypeVideo {
+                return tbox, nil
+            }
+        }
+    }
+    return nil, fmt.Errorf("can't find video trak box in moov")
+}
+
+func (v *Mp4MovieBox) Audio() (*Mp4TrackBox, error) {
+    for _, box := range v.Boxes {
+        if tbox, ok := box.(*Mp4TrackBox); ok {
+            if tbox.TrackType() == SrsMp4TrackTypeAudio {
+                return tbox, nil
+            }
+        }
+    }
+    return nil, fmt.Errorf("can't find audio trak box in moov")
+}
+
+// Get all tracks, in the order of file.
+func (v *Mp4MovieBox) Tracks() (tracks []*Mp4TrackBox) {
+    for _, box := range v.Boxes {
+        if tbox, ok := box.(*Mp4TrackBox); ok {
+            tracks = append(tracks, tbox)
+        }
+    }
+    return
+}
+
+// Get the number of video tracks
+func (v *Mp4MovieBox) NbVideoTracks() (nb_tracks int) {
+    for _, box := range v.Boxes {
+        if tbox, ok := box.(*Mp4TrackBox); ok {
+            if tbox.TrackType() == SrsMp4TrackTypeVideo {
+                nb_tracks ++
+            }
+        }
+    }
+    return
+}
+
+// Get the number of audio tracks
+func (v *Mp4MovieBox) NbSoundTracks() (nb_tracks int) {
+    for _, box := range v.Boxes {
+        if tbox, ok := box.(*Mp4TrackBox); ok {
+            if tbox.TrackType() == SrsMp4TrackTypeAudio {
+                nb_tracks ++
+            }
+        }
+    }
+    return
+}
+
+func (v *Mp4MovieBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4MovieBox) NbHeader() int {
+    return v.Mp4Box.NbHeader()
+}
+
+/**
+ * 4.2 Object Structure
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 17
+ */
+type Mp4FullBox struct {
+    Mp4Box
+    Version uint8
+    Flags uint32
+}
+
+func (v *Mp4FullBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4FullBox) Full() *Mp4FullBox {
+    return v
+}
+
+func (v *Mp4FullBox) NbHeader() int {
+    return v.Mp4Box.NbHeader() + 1 + 3
+}
+
+func (v *Mp4FullBox) DecodeHeader(r io.Reader) (err error) {
+    /*if err = v.Mp4Box.DecodeHeader(r); err != nil {
+        return
+    }*/
+
+    if err = v.Read(r, &v.Flags); err != nil {
+        ol.E(nil, fmt.Sprintf("read moov flags failed, err is %v", err))
+        return
+    }
+
+    v.Version = uint8((v.Flags >> 24) & 0xff)
+    v.Flags = v.Flags & 0x00ffffff
+
+    return
+}
+
+func (v *Mp4FullBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4Box.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, uint32(v.Version) << 24 | (v.Flags & 0x00ffffff)); err != nil {
+        ol.E(nil, fmt.Sprintf("write version and flags failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 8.2.2 Movie Header Box (mvhd)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 31
+ */
+type Mp4MovieHeaderBox struct {
+    Mp4FullBox
+    // an integer that declares the creation time of the presentation (in seconds since
+    // midnight, Jan. 1, 1904, in UTC time)
+    CreateTime uint64
+    // an integer that declares the most recent time the presentation was modified (in
+    // seconds since midnight, Jan. 1, 1904, in UTC time)
+    ModTime uint64
+    // an integer that specifies the time-scale for the entire presentation; this is the number of
+    // time units that pass in one second. For example, a time coordinate system that measures time in
+    // sixtieths of a second has a time scale of 60.
+    TimeScale uint32
+    // an integer that declares length of the presentation (in the indicated timescale). This property
+    // is derived from the presentation’s tracks: the value of this field corresponds to the duration of the
+    // longest track in the presentation. If the duration cannot be determined then duration is set to all 1s.
+    DurationInTbn uint64
+    // a fixed point 16.16 number that indicates the preferred rate to play the presentation; 1.0
+    // (0x00010000) is normal forward playback
+    Rate uint32
+    // a fixed point 8.8 number that indicates the preferred playback volume. 1.0 (0x0100) is full volume.
+    Volume uint16
+    Reserved0 uint16
+    Reserved1 uint64
+    // a transformation matrix for the video; (u,v,w) are restricted here to (0,0,1), hex values (0,0,0x40000000).
+    Matrix [9]int32
+    PreDefined [6]uint32
+    // a non-zero integer that indicates a value to use for the track ID of the next track to be
+    // added to this presentation. Zero is not a valid track ID value. The value of next_track_ID shall be
+    // larger than the largest track-ID in use. If this value is equal to all 1s (32-bit maxint), and a new media
+    // track is to be added, then a search must be made in the file for an unused track identifier.
+    NextTrackId uint32
+    // The bytes after the fields, kept as is for encoding.
+    extra []uint8
+}
+
+func NewMp4MovieHeaderBox() *Mp4MovieHeaderBox {
+    v := &Mp4MovieHeaderBox{
+        Matrix: [9]int32{},
+        PreDefined: [6]uint32{},
+    }
+    return v
+}
+
+// Get the duration in ms
+func (v *Mp4MovieHeaderBox) Duration() uint64 {
+    if v.TimeScale > 0 {
+        return v.DurationInTbn * 1000 / uint64(v.TimeScale)
+    }
+    return 0
+}
+
+func (v *Mp4MovieHeaderBox) Basic() *Mp4Box {
+    return &v.Mp4FullBox.Mp4Box
+}
+
+func (v *Mp4MovieHeaderBox) NbHeader() int {
+    size := v.Mp4FullBox.NbHeader()
+    if v.Version == 1 {
+        size += 8 + 8 + 4 + 8
+    } else {
+        size += 4 + 4 + 4 + 4
+    }
+    return size + 4 + 2 + 2 + 8 + 36 + 24 + 4 + len(v.extra)
+}
+
+func (v *Mp4MovieHeaderBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if v.Version == 1 {
+        if err = v.Read(r, &v.CreateTime); err != nil {
+            ol.E(nil, fmt.Sprintf("read mvhd create time failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.ModTime); err != nil {
+            ol.E(nil, fmt.Sprintf("read mvhd mod time failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.TimeScale); err != nil {
+            ol.E(nil, fmt.Sprintf("read mvhd time scale failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.DurationInTbn); err != nil {
+            ol.E(nil, fmt.Sprintf("read mvhd duration failed, err is %v", err))
+            return
+        }
+    } else {
+        var tmp uint32
+        if err = v.Read(r, &tmp); err != nil {
+            ol.E(nil, fmt.Sprintf("read mvhd create time failed, err is %v", err))
+            return
+        }
+        v.CreateTime = uint64(tmp)
+
+        if err = v.Read(r, &tmp); err != nil {
+            ol.E(nil, fmt.Sprintf("read mvhd mod time failed, err is %v", err))
+            return
+        }
+        v.ModTime = uint64(tmp)
+
+        if err = v.Read(r, &v.TimeScale); err != nil {
+            ol.E(nil, fmt.Sprintf("read mvhd time scale failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &tmp); err != nil {
+            ol.E(nil, fmt.Sprintf("read mvhd duration failed, err is %v", err))
+            return
+        }
+        v.DurationInTbn = uint64(tmp)
+    }
+
+    if err = v.Read(r, &v.Rate); err != nil {
+        ol.E(nil, fmt.Sprintf("read mvhd rate failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Volume); err != nil {
+        ol.E(nil, fmt.Sprintf("read mvhd volume failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Reserved0); err != nil {
+        ol.E(nil, fmt.Sprintf("read mvhd reserved failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Reserved1); err != nil {
+        ol.E(nil, fmt.Sprintf("read mvhd reserved failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < len(v.Matrix); i ++ {
+        if err = v.Read(r, &v.Matrix[i]); err != nil {
+            ol.E(nil, fmt.Sprintf("read mvhd matrix %d failed, err is %v", i, err))
+            return
+        }
+    }
+
+    for i := 0; i < len(v.PreDefined); i ++ {
+        if err = v.Read(r, &v.PreDefined[i]); err != nil {
+            ol.E(nil, fmt.Sprintf("read mvhd predefined %d failed, err is %v", i, err))
+            return
+        }
+    }
+
+    if err = v.Read(r, &v.NextTrackId); err != nil {
+        ol.E(nil, fmt.Sprintf("read mvhd next track id failed, err is %v", err))
+        return
+    }
+
+    if left := v.left(); left > 0 {
+        v.extra = make([]uint8, left)
+        if err = v.Read(r, v.extra); err != nil {
+            ol.E(nil, fmt.Sprintf("read mvhd extra %v bytes failed, err is %v", left, err))
+            return
+        }
+    }
+    return
+}
+
+func (v *Mp4MovieHeaderBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    if v.Version == 1 {
+        err = v.Write(w, v.CreateTime, v.ModTime, v.TimeScale, v.DurationInTbn)
+    } else {
+        err = v.Write(w, uint32(v.CreateTime), uint32(v.ModTime), v.TimeScale, uint32(v.DurationInTbn))
+    }
+    if err != nil {
+        ol.E(nil, fmt.Sprintf("write mvhd times failed, err is %v", err))
+        return
+    }
+
+    if err = v.Write(w, v.Rate, v.Volume, v.Reserved0, v.Reserved1, v.Matrix, v.PreDefined, v.NextTrackId, v.extra); err != nil {
+        ol.E(nil, fmt.Sprintf("write mvhd failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 8.3.1 Track Box (trak)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 32
+ * This is a container box for a single track of a presentation. A presentation consists of one or more tracks.
+ * Each track is independent of the other tracks in the presentation and carries its own temporal and spatial
+ * information. Each track will contain its associated Media Box.
+ */
+type Mp4TrackBox struct {
+    Mp4Box
+    // The samples resolved from the sample table, see Samples.
+    samples []*Mp4Sample
+}
+
+func (v *Mp4TrackBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4TrackBox) NbHeader() int {
+    return v.Mp4Box.NbHeader()
+}
+
+func (v *Mp4TrackBox) VideoCodec() (codec int) {
+    codec = SrsVideoCodecIdForbidden
+    if box, err := v.Stsd(); err != nil {
+        return
+    } else if len(box.Entries) == 0 {
+        return
+    } else {
+        switch box.Entries[0].Basic().BoxType {
+        case SrsMp4BoxTypeAVC1:
+            codec = SrsVideoCodecIdAVC
+        case SrsMp4BoxTypeHVC1, SrsMp4BoxTypeHEV1:
+            codec = SrsVideoCodecIdHEVC
+        case SrsMp4BoxTypeAV01:
+            codec = SrsVideoCodecIdAV1
+        case SrsMp4BoxTypeVP09:
+            codec = SrsVideoCodecIdVP9
+        case SrsMp4BoxTypeVP08:
+            codec = SrsVideoCodecIdVP8
+        }
+    }
+    return
+}
+
+func (v *Mp4TrackBox) SoundCodec() (codec int) {
+    codec = SrsAudioCodecIdForbidden
+    if box, err := v.Stsd(); err != nil {
+        return
+    } else if len(box.Entries) == 0 {
+        return
+    } else {
+        switch box.Entries[0].Basic().BoxType {
+        case SrsMp4BoxTypeMP4A:
+            codec = SrsAudioCodecIdAAC
+        case SrsMp4BoxTypeOPUS:
+            codec = SrsAudioCodecIdOpus
+        case SrsMp4BoxTypeAC3:
+            codec = SrsAudioCodecIdAC3
+        case SrsMp4BoxTypeEC3:
+            codec = SrsAudioCodecIdEAC3
+        case SrsMp4BoxTypeFLAC:
+            codec = SrsAudioCodecIdFLAC
+        case SrsMp4BoxTypeIPCM, SrsMp4BoxTypeFPCM, SrsMp4BoxTypeLPCM, SrsMp4BoxTypeTWOS, SrsMp4BoxTypeSOWT:
+            codec = SrsAudioCodecIdLinearPCMBigEndian
+            if entry := box.Entries[0].(*Mp4AudioSampleEntry); entry.LittleEndian() {
+                codec = SrsAudioCodecIdLinearPCMLittleEndian
+            }
+        }
+    }
+    return
+}
+
+func (v *Mp4TrackBox) TrackType() int {
+    if box, err := v.Get(SrsMp4BoxTypeMDIA); err != nil {
+        return SrsMp4TrackTypeForbidden
+    } else {
+        mdia := box.(*Mp4MediaBox)
+        return mdia.TrackType()
+    }
+}
+
+func (v *Mp4TrackBox) Stsc() (*Mp4Sample2ChunkBox, error) {
+    if box, err := v.Stbl(); err != nil {
+        return nil, err
+    } else {
+        return box.Stsc()
+    }
+}
+
+func (v *Mp4TrackBox) Stts() (*Mp4DecodingTime2SampleBox, error) {
+    if box, err := v.Stbl(); err != nil {
+        return nil, err
+    } else {
+        return box.Stts()
+    }
+}
+
+func (v *Mp4TrackBox) Ctts() (*Mp4CompositionTime2SampleBox, error) {
+    if box, err := v.Stbl(); err != nil {
+        return nil, err
+    } else {
+        return box.Ctts()
+    }
+}
+
+// Get the sample sizes, the stsz or stz2.
+func (v *Mp4TrackBox) Stsz() (SampleSizeBox, error) {
+    if box, err := v.Stbl(); err != nil {
+        return nil, err
+    } else {
+        return box.Stsz()
+    }
+}
+
+func (v *Mp4TrackBox) Stss() (*Mp4SyncSampleBox, error) {
+    if box, err := v.Stbl(); err != nil {
+        return nil, err
+    } else {
+        return box.Stss()
+    }
+}
+
+// Get the chunk offsets, the stco or co64.
+func (v *Mp4TrackBox) Stco() (ChunkOffsetBox, error) {
+    if box, err := v.Stbl(); err != nil {
+        return nil, err
+    } else {
+        return box.Stco()
+    }
+}
+
+func (v *Mp4TrackBox) Mdhd() (*Mp4MediaHeaderBox, error) {
+    if box, err := v.Mdia(); err != nil {
+        return nil, err
+    } else {
+        return box.Mdhd()
+    }
+}
+
+func (v *Mp4TrackBox) Tkhd() (*Mp4TrackHeaderBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeTKHD); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4TrackHeaderBox), nil
+    }
+}
+
+func (v *Mp4TrackBox) Edts() (*Mp4EditBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeEDTS); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4EditBox), nil
+    }
+}
+
+func (v *Mp4TrackBox) Elst() (*Mp4EditListBox, error) {
+    if box, err := v.Edts(); err != nil {
+        return nil, err
+    } else {
+        return box.Elst()
+    }
+}
+
+func (v *Mp4TrackBox) Hdlr() (*Mp4HandlerReferenceBox, error) {
+    if box, err := v.Mdia(); err != nil {
+        return nil, err
+    } else {
+        return box.Hdlr()
+    }
+}
+
+func (v *Mp4TrackBox) Mdia() (*Mp4MediaBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeMDIA); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4MediaBox), nil
+    }
+}
+
+func (v *Mp4TrackBox) Minf() (*Mp4MediaInformationBox, error) {
+    if box, err := v.Mdia(); err != nil {
+        return nil, err
+    } else {
+        return box.Minf()
+    }
+}
+
+func (v *Mp4TrackBox) Dref() (*Mp4DataReferenceBox, error) {
+    if box, err := v.Minf(); err != nil {
+        return nil, err
+    } else if dinf, err := box.Dinf(); err != nil {
+        return nil, err
+    } else {
+        return dinf.Dref()
+    }
+}
+
+// Get the locations of media data in other files, which are referenced by the sample entries,
+// empty when all media data is in this file.
+func (v *Mp4TrackBox) ExternalData() (locations []string) {
+    dref, err := v.Dref()
+    if err != nil {
+        return
+    }
+    stsd, err := v.Stsd()
+    if err != nil {
+        return
+    }
+    for _, box := range stsd.Entries {
+        entry, ok := box.(SampleEntryBox)
+        if !ok {
+            continue
+        }
+        if de, err := dref.Entry(int(entry.Entry().DataReferenceIndex)); err == nil && !de.SelfContained() {
+            locations = append(locations, de.DataLocation())
+        }
+    }
+    return
+}
+
+func (v *Mp4TrackBox) Stbl() (*Mp4SampleTableBox, error) {
+    if box, err := v.Minf(); err != nil {
+        return nil, err
+    } else {
+        return box.Stbl()
+    }
+}
+
+func (v *Mp4TrackBox) Stsd() (*Mp4SampleDescritionBox, error) {
+    if box, err := v.Stbl(); err != nil {
+        return nil, err
+    } else {
+        return box.Stsd()
+    }
+}
+
+func (v *Mp4TrackBox) Mp4a() (*Mp4AudioSampleEntry, error) {
+    if box, err := v.Stsd(); err != nil {
+        return nil, err
+    } else {
+        return box.Mp4a()
+    }
+}
+
+func (v *Mp4TrackBox) Avc1() (*Mp4VisualSampleEntry, error) {
+    if box, err := v.Stsd(); err != nil {
+        return nil, err
+    } else {
+        return box.Avc1()
+    }
+}
+
+func (v *Mp4TrackBox) Avcc() (*Mp4AvccBox, error) {
+    if box, err := v.Avc1(); err != nil {
+        return nil, err
+    } else {
+        return box.Avcc()
+    }
+}
+
+func (v *Mp4TrackBox) Hvc1() (*Mp4VisualSampleEntry, error) {
+    if box, err := v.Stsd(); err != nil {
+        return nil, err
+    } else {
+        return box.Hvc1()
+    }
+}
+
+func (v *Mp4TrackBox) Hvcc() (*Mp4HvccBox, error) {
+    if box, err := v.Hvc1(); err != nil {
+        return nil, err
+    } else {
+        return box.Hvcc()
+    }
+}
+
+func (v *Mp4TrackBox) Av01() (*Mp4VisualSampleEntry, error) {
+    if box, err := v.Stsd(); err != nil {
+        return nil, err
+    } else {
+        return box.Av01()
+    }
+}
+
+func (v *Mp4TrackBox) Av1c() (*Mp4Av1cBox, error) {
+    if box, err := v.Av01(); err != nil {
+        return nil, err
+    } else {
+        return box.Av1c()
+    }
+}
+
+func (v *Mp4TrackBox) Vp09() (*Mp4VisualSampleEntry, error) {
+    if box, err := v.Stsd(); err != nil {
+        return nil, err
+    } else {
+        return box.Vp09()
+    }
+}
+
+func (v *Mp4TrackBox) Vpcc() (*Mp4VpccBox, error) {
+    if box, err := v.Vp09(); err != nil {
+        return nil, err
+    } else {
+        return box.Vpcc()
+    }
+}
+
+func (v *Mp4TrackBox) Visual() (*Mp4VisualSampleEntry, error) {
+    if box, err := v.Stsd(); err != nil {
+        return nil, err
+    } else {
+        return box.Visual()
+    }
+}
+
+func (v *Mp4TrackBox) AacConfig() (*Mp4AacConfig, error) {
+    if box, err := v.Asc(); err != nil {
+        return nil, err
+    } else {
+        return box.AacConfig()
+    }
+}
+
+func (v *Mp4TrackBox) Opus() (*Mp4AudioSampleEntry, error) {
+    if box, err := v.Stsd(); err != nil {
+        return nil, err
+    } else {
+        return box.Opus()
+    }
+}
+
+func (v *Mp4TrackBox) Dops() (*Mp4OpusSpecificBox, error) {
+    if box, err := v.Opus(); err != nil {
+        return nil, err
+    } else {
+        return box.Dops()
+    }
+}
+
+func (v *Mp4TrackBox) Audio() (*Mp4AudioSampleEntry, error) {
+    if box, err := v.Stsd(); err != nil {
+        return nil, err
+    } else {
+        return box.Audio()
+    }
+}
+
+func (v *Mp4TrackBox) Asc() (*Mp4DecoderSpecificInfo, error) {
+    if box, err := v.Mp4a(); err != nil {
+        return nil, err
+    } else {
+        return box.Asc()
+    }
+}
+
+/**
+ * 8.3.2 Track Header Box (tkhd)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 32
+ */
+type Mp4TrackHeaderBox struct {
+    Mp4FullBox
+    // an integer that declares the creation time of the presentation (in seconds since
+    // midnight, Jan. 1, 1904, in UTC time)
+    CreateTime uint64
+    ModTime uint64
+    TrackId uint32
+    Reserved0 uint32
+    Duration uint64
+    Reserved1 uint64
+    Layer int16
+    AlternateGroup int16
+    Volume int16
+    Reserved2 uint16
+    Matrix [9]int32
+    Width int32
+    Height int32
+}
+
+func NewMp4TrackHeaderBox() *Mp4TrackHeaderBox {
+    v := &Mp4TrackHeaderBox{
+        Matrix: [9]int32{0x00010000, 0, 0, 0, 0x00010000, 0, 0, 0, 0x40000000},
+    }
+    v.Flags = 0x03
+    return v
+}
+
+func (v *Mp4TrackHeaderBox) Basic() *Mp4Box {
+    return &v.Mp4FullBox.Mp4Box
+}
+
+func (v *Mp4TrackHeaderBox) NbHeader() int {
+    size := v.Mp4FullBox.NbHeader()
+    if v.Version == 1 {
+        size += 8 + 8 + 4 + 4 + 8
+    } else {
+        size += 4 + 4 + 4 + 4 + 4
+    }
+    return size + 8 + 2 + 2 + 2 + 2 + 36 + 4 + 4
+}
+
+func (v *Mp4TrackHeaderBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if v.Version == 1 {
+        if err = v.Read(r, &v.CreateTime); err != nil {
+            ol.E(nil, fmt.Sprintf("tkhd read create time failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.ModTime); err != nil {
+            ol.E(nil, fmt.Sprintf("tkhd read mod time failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.TrackId); err != nil {
+            ol.E(nil, fmt.Sprintf("tkhd read track id failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.Reserved0); err != nil {
+            ol.E(nil, fmt.Sprintf("tkhd read reserved failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.Duration); err != nil {
+            ol.E(nil, fmt.Sprintf("tkhd read duration failed, err is %v", err))
+            return
+        }
+    } else {
+        var tmp uint32
+        if err = v.Read(r, &tmp); err != nil {
+            ol.E(nil, fmt.Sprintf("tkhd read create time failed, err is %v", err))
+            return
+        }
+        v.CreateTime = uint64(tmp)
+
+        if err = v.Read(r, &tmp); err != nil {
+            ol.E(nil, fmt.Sprintf("tkhd mod time failed, err is %v", err))
+            return
+        }
+        v.ModTime = uint64(tmp)
+
+        if err = v.Read(r, &v.TrackId); err != nil {
+            ol.E(nil, fmt.Sprintf("tkhd read track id failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.Reserved0); err != nil {
+            ol.E(nil, fmt.Sprintf("tkhd read reserved failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &tmp); err != nil {
+            ol.E(nil, fmt.Sprintf("tkhd read duration failed, err is %v", err))
+            return
+        }
+        v.Duration = uint64(tmp)
+    }
+
+    if err = v.Read(r, &v.Reserved1); err != nil {
+        ol.E(nil, fmt.Sprintf("read tkhd reserved failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Layer); err != nil {
+        ol.E(nil, fmt.Sprintf("read tkhd layer failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.AlternateGroup); err != nil {
+        ol.E(nil, fmt.Sprintf("read tkhd alternate froup failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Volume); err != nil {
+        ol.E(nil, fmt.Sprintf("read tkhd volume failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Reserved2); err != nil {
+        ol.E(nil, fmt.Sprintf("read tkhd reserved failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < len(v.Matrix); i ++ {
+        if err = v.Read(r, &v.Matrix[i]); err != nil {
+            ol.E(nil, fmt.Sprintf("read tkhd matrix %d failed, err is %v", i, err))
+            return
+        }
+    }
+
+    // The width and height are kept in 16.16, see DisplayWidth and DisplayHeight.
+    if err = v.Read(r, &v.Width); err != nil {
+        ol.E(nil, fmt.Sprintf("read tkhd width failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Height); err != nil {
+        ol.E(nil, fmt.Sprintf("read tkhd height failed, err is %v", err))
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode tkhd:%+v", v))
+    return
+}
+
+func (v *Mp4TrackHeaderBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    if v.Version == 1 {
+        err = v.Write(w, v.CreateTime, v.ModTime, v.TrackId, v.Reserved0, v.Duration)
+    } else {
+        err = v.Write(w, uint32(v.CreateTime), uint32(v.ModTime), v.TrackId, v.Reserved0, uint32(v.Duration))
+    }
+    if err != nil {
+        ol.E(nil, fmt.Sprintf("write tkhd times failed, err is %v", err))
+        return
+    }
+
+    if err = v.Write(w, v.Reserved1, v.Layer, v.AlternateGroup, v.Volume, v.Reserved2, v.Matrix, v.Width, v.Height); err != nil {
+        ol.E(nil, fmt.Sprintf("write tkhd failed, err is %v", err))
+        return
+    }
+    return
+}
+
+// Get the visual presentation width, converted from 16.16.
+func (v *Mp4TrackHeaderBox) DisplayWidth() float64 {
+    return float64(v.Width) / 0x10000
+}
+
+// Get the visual presentation height, converted from 16.16.
+func (v *Mp4TrackHeaderBox) DisplayHeight() float64 {
+    return float64(v.Height) / 0x10000
+}
+
+/**
+ * 8.6.5 Edit Box (edts)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 54
+ * An Edit Box maps the presentation time-line to the media time-line as it is stored in the file.
+ * The Edit Box is a container for the edit lists.
+ */
+type Mp4EditBox struct {
+    Mp4Box
+}
+
+func (v *Mp4EditBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4EditBox) Elst() (*Mp4EditListBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeELST); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4EditListBox), nil
+    }
+}
+
+/**
+ * 8.6.6 Edit List Box
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 55
+ */
+type Mp4ElstEntry struct {
+    // an integer that specifies the duration of this edit segment in units of the timescale
+    // in the Movie Header Box
+    SegmentDuration uint64 `json:"segment_duration"`
+    // an integer containing the starting time within the media of this edit segment (in media time
+    // scale units, in composition time). If this field is set to –1, it is an empty edit. The last edit in a track
+    // shall never be an empty edit.
+    MediaTime int64 `json:"media_time"`
+    // specifies the relative rate at which to play the media corresponding to this edit segment. If this
+    // value is 0, then the edit is specifying a ‘dwell’: the media at media-time is presented for the segment-duration.
+    MediaRateInteger int16 `json:"media_rate_integer"`
+    MediaRateFraction int16 `json:"media_rate_fraction"`
+}
+
+/**
+ * 8.6.6 Edit List Box (elst)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 55
+ * This box contains an explicit timeline map. Each entry defines part of the track time-line: by mapping part of
+ * the media time-line, or by indicating ‘empty’ time, or by defining a ‘dwell’, where a single time-point in the
+ * media is held for a period.
+ */
+type Mp4EditListBox struct {
+    Mp4FullBox
+    // an integer that gives the number of entries in the following table
+    EntryCount uint32
+    Entries []*Mp4ElstEntry
+}
+
+func NewMp4EditListBox() *Mp4EditListBox {
+    v := &Mp4EditListBox{
+        Entries: []*Mp4ElstEntry{},
+    }
+    return v
+}
+
+func (v *Mp4EditListBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4EditListBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read elst entry count failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < int(v.EntryCount); i++ {
+        entry := &Mp4ElstEntry{}
+        if v.Version == 1 {
+            if err = v.Read(r, &entry.SegmentDuration); err == nil {
+                err = v.Read(r, &entry.MediaTime)
+            }
+        } else {
+            var duration uint32
+            var mediaTime int32
+            if err = v.Read(r, &duration); err == nil {
+                err = v.Read(r, &mediaTime)
+            }
+            entry.SegmentDuration, entry.MediaTime = uint64(duration), int64(mediaTime)
+        }
+        if err != nil {
+            ol.E(nil, fmt.Sprintf("read elst %v entry time failed, err is %v", i, err))
+            return
+        }
+
+        if err = v.Read(r, &entry.MediaRateInteger); err != nil {
+            ol.E(nil, fmt.Sprintf("read elst %v entry media rate integer failed, err is %v", i, err))
+            return
+        }
+        if err = v.Read(r, &entry.MediaRateFraction); err != nil {
+            ol.E(nil, fmt.Sprintf("read elst %v entry media rate fraction failed, err is %v", i, err))
+            return
+        }
+        v.Entries = append(v.Entries, entry)
+    }
+
+    ol.T(nil, fmt.Sprintf("decode elst box success, box=%+v", v))
+    return
+}
+
+func (v *Mp4EditListBox) NbHeader() int {
+    size := 4 + 4 + 2 + 2
+    if v.Version == 1 {
+        size = 8 + 8 + 2 + 2
+    }
+    return v.Mp4FullBox.NbHeader() + 4 + size * len(v.Entries)
+}
+
+func (v *Mp4EditListBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    v.EntryCount = uint32(len(v.Entries))
+    if err = v.Write(w, v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("write elst entry count failed, err is %v", err))
+        return
+    }
+
+    for i, entry := range v.Entries {
+        if v.Version == 1 {
+            err = v.Write(w, entry.SegmentDuration, entry.MediaTime)
+        } else {
+            err = v.Write(w, uint32(entry.SegmentDuration), int32(entry.MediaTime))
+        }
+        if err == nil {
+            err = v.Write(w, entry.MediaRateInteger, entry.MediaRateFraction)
+        }
+        if err != nil {
+            ol.E(nil, fmt.Sprintf("write elst %v entry failed, err is %v", i, err))
+            return
+        }
+    }
+    return
+}
+
+/**
+ * 8.4.1 Media Box (mdia)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 36
+ * The media declaration container contains all the objects that declare information about the media data within a
+ * track.
+ */
+type Mp4MediaBox struct {
+    Mp4Box
+}
+
+func (v *Mp4MediaBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4MediaBox) Mdhd() (*Mp4MediaHeaderBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeMDHD); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4MediaHeaderBox), nil
+    }
+}
+
+func (v *Mp4MediaBox) Hdlr() (*Mp4HandlerReferenceBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeHDLR); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4HandlerReferenceBox), nil
+    }
+}
+
+func (v *Mp4MediaBox) Minf() (*Mp4MediaInformationBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeMINF); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4MediaInformationBox), nil
+    }
+}
+
+func (v *Mp4MediaBox) TrackType() int {
+    if box, err := v.Get(SrsMp4BoxTypeHDLR); err != nil {
+        return SrsMp4TrackTypeForbidden
+    } else {
+        hdlr := box.(*Mp4HandlerReferenceBox)
+        if hdlr.HandlerType == SrsMp4HandlerTypeSOUN {
+            return SrsMp4TrackTypeAudio
+        }
+        if hdlr.HandlerType == SrsMp4HandlerTypeVIDE {
+            return SrsMp4TrackTypeVideo
+        }
+    }
+    return SrsMp4TrackTypeForbidden
+}
+
+/**
+ * 8.4.2 Media Header Box (mdhd)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 36
+ * The media declaration container contains all the objects that declare information about the media data within a
+ * track.
+ */
+type Mp4MediaHeaderBox struct {
+    Mp4FullBox
+    // an integer that declares the creation time of the presentation (in seconds since
+    // midnight, Jan. 1, 1904, in UTC time)
+    CreateTime uint64
+    // an integer that declares the most recent time the presentation was modified (in
+    // seconds since midnight, Jan. 1, 1904, in UTC time)
+    ModTime    uint64
+    // an integer that specifies the time-scale for the entire presentation; this is the number of
+    // time units that pass in one second. For example, a time coordinate system that measures time in
+    // sixtieths of a second has a time scale of 60.
+    TimeScale  uint32
+    // an integer that declares length of the presentation (in the indicated timescale). This property
+    // is derived from the presentation’s tracks: the value of this field corresponds to the duration of the
+    // longest track in the presentation. If the duration cannot be determined then duration is set to all 1s.
+    Duration   uint64
+    // the language code for this media. See ISO 639-2/T for the set of three character
+    // codes. Each character is packed as the difference between its ASCII value and 0x60. Since the code
+    // is confined to being three lower-case letters, these values are strictly positive.
+    Language   uint16
+    PreDefined uint16
+}
+
+func (v *Mp4MediaHeaderBox) Basic() *Mp4Box {
+    return &v.Mp4FullBox.Mp4Box
+}
+
+func (v *Mp4MediaHeaderBox) NbHeader() int {
+    size := v.Mp4FullBox.NbHeader()
+    if v.Version == 1 {
+        size += 8 + 8 + 4 + 8
+    } else {
+        size += 4 + 4 + 4 + 4
+    }
+    return size + 2 + 2
+}
+
+func (v *Mp4MediaHeaderBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if v.Version == 1 {
+        if err = v.Read(r, &v.CreateTime); err != nil {
+            ol.E(nil, fmt.Sprintf("mdhd read create time failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.ModTime); err != nil {
+            ol.E(nil, fmt.Sprintf("mdhd read mod time failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.TimeScale); err != nil {
+            ol.E(nil, fmt.Sprintf("mdhd read timescale failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.Duration); err != nil {
+            ol.E(nil, fmt.Sprintf("tkhd read duration failed, err is %v", err))
+            return
+        }
+    } else {
+        var tmp uint32
+        if err = v.Read(r, &tmp); err != nil {
+            ol.E(nil, fmt.Sprintf("mdhd read create time failed, err is %v", err))
+            return
+        }
+        v.CreateTime = uint64(tmp)
+
+        if err = v.Read(r, &tmp); err != nil {
+            ol.E(nil, fmt.Sprintf("mdhd mod time failed, err is %v", err))
+            return
+        }
+        v.ModTime = uint64(tmp)
+
+        if err = v.Read(r, &v.TimeScale); err != nil {
+            ol.E(nil, fmt.Sprintf("mdhd read time scale failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &tmp); err != nil {
+            ol.E(nil, fmt.Sprintf("mdhd read duration failed, err is %v", err))
+            return
+        }
+        v.Duration = uint64(tmp)
+    }
+
+    if err = v.Read(r, &v.Language); err != nil {
+        ol.E(nil, fmt.Sprintf("mdhd read language failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.PreDefined); err != nil {
+        ol.E(nil, fmt.Sprintf("mdhd read predefined failed, err is %v", err))
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode mdhd box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4MediaHeaderBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    if v.Version == 1 {
+        err = v.Write(w, v.CreateTime, v.ModTime, v.TimeScale, v.Duration)
+    } else {
+        err = v.Write(w, uint32(v.CreateTime), uint32(v.ModTime), v.TimeScale, uint32(v.Duration))
+    }
+    if err != nil {
+        ol.E(nil, fmt.Sprintf("write mdhd times failed, err is %v", err))
+        return
+    }
+
+    if err = v.Write(w, v.Language, v.PreDefined); err != nil {
+        ol.E(nil, fmt.Sprintf("write mdhd language failed, err is %v", err))
+        return
+    }
+    return
+}
+
+// Get the ISO 639-2/T language code, for example, "eng".
+func (v *Mp4MediaHeaderBox) LanguageCode() string {
+    b := []byte{
+        uint8((v.Language >> 10) & 0x1f) + 0x60,
+        uint8((v.Language >> 5) & 0x1f) + 0x60,
+        uint8(v.Language & 0x1f) + 0x60,
+    }
+    return string(b)
+}
+
+/**
+ * 8.4.3 Handler Reference Box (hdlr)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 37
+ * This box within a Media Box declares the process by which the media-data in the track is presented, and thus,
+ * the nature of the media in a track. For example, a video track would be handled by a video handler.
+ */
+type Mp4HandlerReferenceBox struct {
+    Mp4FullBox
+    PreDefined uint32
+    // an integer containing one of the following values, or a value from a derived specification:
+    //      ‘vide’, Video track
+    //      ‘soun’, Audio track
+    HandlerType uint32
+    Reserved [3]uint32
+    // a null-terminated string in UTF-8 characters which gives a human-readable name for the track
+    // type (for debugging and inspection purposes).
+    Name string
+}
+
+func NewMp4HandlerReferenceBox() *Mp4HandlerReferenceBox {
+    v := &Mp4HandlerReferenceBox{
+        Reserved: [3]uint32{},
+    }
+    return v
+}
+
+func (v *Mp4HandlerReferenceBox) Basic() *Mp4Box {
+    return &v.Mp4FullBox.Mp4Box
+}
+
+func (v *Mp4HandlerReferenceBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 4 + 4 + 12 + len(v.Name)
+}
+
+func (v *Mp4HandlerReferenceBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.PreDefined); err != nil {
+        ol.E(nil, fmt.Sprintf("read hdlr predefined failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.HandlerType); err != nil {
+        ol.E(nil, fmt.Sprintf("read hdlr handler type failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < len(v.Reserved); i ++ {
+        if err = v.Read(r, &v.Reserved[i]); err != nil {
+            ol.E(nil, fmt.Sprintf("read hdlr reserved %d failed, err is %v", i, err))
+            return
+        }
+    }
+
+    data := make([]uint8, v.left())
+    if err = v.Read(r, data); err != nil {
+        ol.E(nil, fmt.Sprintf("read hdlr name failed, err is %v", err))
+        return
+    }
+    v.Name = string(data)
+
+    ol.T(nil, fmt.Sprintf("decode hdlr box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4HandlerReferenceBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.PreDefined, v.HandlerType, v.Reserved, []uint8(v.Name)); err != nil {
+        ol.E(nil, fmt.Sprintf("write hdlr failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 8.4.4 Media Information Box (minf)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 38
+ * This box contains all the objects that declare characteristic information of the media in the track.
+ */
+type Mp4MediaInformationBox struct {
+    Mp4Box
+}
+
+func (v *Mp4MediaInformationBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4MediaInformationBox) Dinf() (*Mp4DataInformationBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeDINF); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4DataInformationBox), nil
+    }
+}
+
+func (v *Mp4MediaInformationBox) Stbl() (*Mp4SampleTableBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeSTBL); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4SampleTableBox), nil
+    }
+}
+
+/**
+ * 8.4.5.2 Video Media Header Box (vmhd)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 38
+ * The video media header contains general presentation information, independent of the coding, for video
+ * media. Note that the flags field has the value 1.
+ */
+type Mp4VideoMediaHeaderBox struct {
+    Mp4FullBox
+    GraphicsMode uint16
+    Opcolor [3]uint16
+}
+
+func NewMp4VideoMediaHeaderBox() *Mp4VideoMediaHeaderBox {
+    v := &Mp4VideoMediaHeaderBox{
+        Opcolor: [3]uint16{},
+    }
+    v.Mp4FullBox.Version = 0
+    v.Mp4FullBox.Flags = 1
+    return v
+}
+
+func (v *Mp4VideoMediaHeaderBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4VideoMediaHeaderBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 2 + 6
+}
+
+func (v *Mp4VideoMediaHeaderBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.GraphicsMode); err != nil {
+        ol.E(nil, fmt.Sprintf("read vmhd graphics mode failed, err is %v", err))
+        return
+    }
+
+    err = v.Read(r, &v.Opcolor[0])
+    err = v.Read(r, &v.Opcolor[1])
+    err = v.Read(r, &v.Opcolor[2])
+
+    ol.T(nil, fmt.Sprintf("decode vmhd box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4VideoMediaHeaderBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.GraphicsMode, v.Opcolor); err != nil {
+        ol.E(nil, fmt.Sprintf("write vmhd failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 8.4.5.3 Sound Media Header Box (smhd)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 39
+ * The sound media header contains general presentation information, independent of the coding, for audio
+ * media. This header is used for all tracks containing audio.
+ */
+type Mp4SoundMediaHeaderBox struct {
+    Mp4FullBox
+    // A fixed-point 8.8 number that places mono audio tracks in a stereo space; 0 is centre (the
+    // normal value); full left is -1.0 and full right is 1.0.
+    Balance int16
+    Reserved uint16
+}
+
+func NewMp4SoundMediaHeaderBox() *Mp4SoundMediaHeaderBox {
+    v := &Mp4SoundMediaHeaderBox{}
+    v.BoxType = SrsMp4BoxTypeSMHD
+    return v
+}
+
+func (v *Mp4SoundMediaHeaderBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4SoundMediaHeaderBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 2 + 2
+}
+
+func (v *Mp4SoundMediaHeaderBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.Balance); err != nil {
+        ol.E(nil, fmt.Sprintf("read smhd balance failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Reserved); err != nil {
+        ol.E(nil, fmt.Sprintf("read smhd reserved failed, err is %v", err))
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode smhd box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4SoundMediaHeaderBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.Balance, v.Reserved); err != nil {
+        ol.E(nil, fmt.Sprintf("write smhd failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 8.4.5.4 Hint Media Header Box (hmhd)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 39
+ * The hint media header contains general information, independent of the protocol, for hint tracks.
+ */
+type Mp4HintMediaHeaderBox struct {
+    Mp4FullBox
+    // The size in bytes of the largest PDU in this (hint) stream.
+    MaxPduSize uint16
+    // The average size of a PDU over the entire presentation.
+    AvgPduSize uint16
+    // The maximum rate in bits/second over any window of one second.
+    MaxBitrate uint32
+    // The average rate in bits/second over the entire presentation.
+    AvgBitrate uint32
+    Reserved uint32
+}
+
+func NewMp4HintMediaHeaderBox() *Mp4HintMediaHeaderBox {
+    v := &Mp4HintMediaHeaderBox{}
+    v.BoxType = SrsMp4BoxTypeHMHD
+    return v
+}
+
+func (v *Mp4HintMediaHeaderBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4HintMediaHeaderBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 2 + 2 + 4 + 4 + 4
+}
+
+func (v *Mp4HintMediaHeaderBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.MaxPduSize); err != nil {
+        ol.E(nil, fmt.Sprintf("read hmhd max pdu size failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.AvgPduSize); err != nil {
+        ol.E(nil, fmt.Sprintf("read hmhd avg pdu size failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.MaxBitrate); err != nil {
+        ol.E(nil, fmt.Sprintf("read hmhd max bitrate failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.AvgBitrate); err != nil {
+        ol.E(nil, fmt.Sprintf("read hmhd avg bitrate failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Reserved); err != nil {
+        ol.E(nil, fmt.Sprintf("read hmhd reserved failed, err is %v", err))
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode hmhd box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4HintMediaHeaderBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.MaxPduSize, v.AvgPduSize, v.MaxBitrate, v.AvgBitrate, v.Reserved); err != nil {
+        ol.E(nil, fmt.Sprintf("write hmhd failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 8.4.5.5 Null Media Header Box (nmhd)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 39
+ * Streams other than visual and audio (e.g., timed metadata streams) may use a null Media Header Box.
+ * It has no fields other than the version and flags.
+ */
+type Mp4NullMediaHeaderBox struct {
+    Mp4FullBox
+}
+
+func NewMp4NullMediaHeaderBox() *Mp4NullMediaHeaderBox {
+    v := &Mp4NullMediaHeaderBox{}
+    v.BoxType = SrsMp4BoxTypeNMHD
+    return v
+}
+
+func (v *Mp4NullMediaHeaderBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+/**
+ * 12.6.2 Subtitle Media Header Box (sthd)
+ * ISO_IEC_14496-12-base-format-2015.pdf
+ * Subtitle tracks use the subtitle media header, which has no fields other than the version and flags.
+ */
+type Mp4SubtitleMediaHeaderBox struct {
+    Mp4FullBox
+}
+
+func NewMp4SubtitleMediaHeaderBox() *Mp4SubtitleMediaHeaderBox {
+    v := &Mp4SubtitleMediaHeaderBox{}
+    v.BoxType = SrsMp4BoxTypeSTHD
+    return v
+}
+
+func (v *Mp4SubtitleMediaHeaderBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+/**
+ * 8.7.1 Data Information Box (dinf)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 56
+ * The data information box contains objects that declare the location of the media information in a track.
+ */
+type Mp4DataInformationBox struct {
+    Mp4Box
+}
+
+func (v *Mp4DataInformationBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4DataInformationBox) Dref() (*Mp4DataReferenceBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeDREF); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4DataReferenceBox), nil
+    }
+}
+
+// The data entry of dref, see Mp4DataEntryUrlBox and Mp4DataEntryUrnBox.
+type DataEntryBox interface {
+    FullBox
+    // Whether the media data is in the same file as the moov.
+    SelfContained() bool
+    // Get the location of the media data, empty when self-contained.
+    DataLocation() string
+}
+
+/**
+ * 8.7.2 Data Reference Box (dref)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 56
+ * The data reference object contains a table of data references (normally URLs) that declare the location(s) of
+ * the media data used within the presentation. The data reference index in the sample description ties entries
+ * in this table to the samples in the track.
+ */
+type Mp4DataReferenceBox struct {
+    Mp4FullBox
+    // The entries are decoded as the contained boxes, the entry_count is updated when encoding.
+    EntryCount uint32
+}
+
+func NewMp4DataReferenceBox() *Mp4DataReferenceBox {
+    v := &Mp4DataReferenceBox{}
+    v.BoxType = SrsMp4BoxTypeDREF
+    return v
+}
+
+func (v *Mp4DataReferenceBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4DataReferenceBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read dref entry count failed, err is %v", err))
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode dref box success, box:%+v", v))
+    return
+}
+
+// The size of header and entry_count, the entries are the contained boxes.
+func (v *Mp4DataReferenceBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 4
+}
+
+func (v *Mp4DataReferenceBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, uint32(len(v.Boxes))); err != nil {
+        ol.E(nil, fmt.Sprintf("write dref entry count failed, err is %v", err))
+        return
+    }
+    return
+}
+
+// Get the data entry by the data reference index of sample entry, which starts from 1.
+func (v *Mp4DataReferenceBox) Entry(index int) (DataEntryBox, error) {
+    if index < 1 || index > len(v.Boxes) {
+        return nil, fmt.Errorf("invalid data reference index %v, entries=%v", index, len(v.Boxes))
+    }
+    if entry, ok := v.Boxes[index - 1].(DataEntryBox); ok {
+        return entry, nil
+    }
+    return nil, fmt.Errorf("unknown data entry %v", FourCC(v.Boxes[index - 1].Basic().BoxType))
+}
+
+/**
+ * 8.7.2 Data Reference Box (url )
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 56
+ * If the flag is set indicating that the data is in the same file as this box, no string is supplied,
+ * otherwise the location is the URL of the media data.
+ */
+type Mp4DataEntryUrlBox struct {
+    Mp4FullBox
+    // The null-terminated URL, kept as stored.
+    Location string
+}
+
+func NewMp4DataEntryUrlBox() *Mp4DataEntryUrlBox {
+    v := &Mp4DataEntryUrlBox{}
+    v.BoxType = SrsMp4BoxTypeURL
+    v.Flags = SRS_MP4_DATA_ENTRY_SELF_CONTAINED
+    return v
+}
+
+func (v *Mp4DataEntryUrlBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4DataEntryUrlBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    data := make([]uint8, v.left())
+    if err = v.Read(r, data); err != nil {
+        ol.E(nil, fmt.Sprintf("read url location failed, err is %v", err))
+        return
+    }
+    v.Location = string(data)
+
+    ol.T(nil, fmt.Sprintf("decode url box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4DataEntryUrlBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + len(v.Location)
+}
+
+func (v *Mp4DataEntryUrlBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, []uint8(v.Location)); err != nil {
+        ol.E(nil, fmt.Sprintf("write url location failed, err is %v", err))
+        return
+    }
+    return
+}
+
+func (v *Mp4DataEntryUrlBox) SelfContained() bool {
+    return v.Flags & SRS_MP4_DATA_ENTRY_SELF_CONTAINED != 0
+}
+
+func (v *Mp4DataEntryUrlBox) DataLocation() string {
+    if v.SelfContained() {
+        return ""
+    }
+    return strings.TrimRight(v.Location, "\x00")
+}
+
+/**
+ * 8.7.2 Data Reference Box (urn )
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 56
+ * The name is a URN and required, the location is optional, both are null-terminated strings.
+ */
+type Mp4DataEntryUrnBox struct {
+    Mp4FullBox
+    // The null-terminated name and location, kept as stored.
+    Name string
+    Location string
+}
+
+func (v *Mp4DataEntryUrnBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4DataEntryUrnBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    data := make([]uint8, v.left())
+    if err = v.Read(r, data); err != nil {
+        ol.E(nil, fmt.Sprintf("read urn name and location failed, err is %v", err))
+        return
+    }
+    if pos := bytes.IndexByte(data, 0); pos >= 0 {
+        v.Name, v.Location = string(data[:pos + 1]), string(data[pos + 1:])
+    } else {
+        v.Name = string(data)
+    }
+
+    ol.T(nil, fmt.Sprintf("decode urn box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4DataEntryUrnBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + len(v.Name) + len(v.Location)
+}
+
+func (v *Mp4DataEntryUrnBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, []uint8(v.Name), []uint8(v.Location)); err != nil {
+        ol.E(nil, fmt.Sprintf("write urn name and location failed, err is %v", err))
+        return
+    }
+    return
+}
+
+func (v *Mp4DataEntryUrnBox) SelfContained() bool {
+    return v.Flags & SRS_MP4_DATA_ENTRY_SELF_CONTAINED != 0
+}
+
+// Get the location, or the name when there is no location.
+func (v *Mp4DataEntryUrnBox) DataLocation() string {
+    if v.SelfContained() {
+        return ""
+    }
+    if location := strings.TrimRight(v.Location, "\x00"); location != "" {
+        return location
+    }
+    return strings.TrimRight(v.Name, "\x00")
+}
+
+/**
+ * 8.5.1 Sample Table Box (stbl)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 40
+ * The sample table contains all the time and data indexing of the media samples in a track. Using the tables
+ * here, it is possible to locate samples in time, determine their type (e.g. I-frame or not), and determine their
+ * size, container, and offset into that container.
+ */
+type Mp4SampleTableBox struct {
+    Mp4Box
+}
+
+func (v *Mp4SampleTableBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4SampleTableBox) Stsc() (*Mp4Sample2ChunkBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeSTSC); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4Sample2ChunkBox), nil
+    }
+}
+
+func (v *Mp4SampleTableBox) Stts() (*Mp4DecodingTime2SampleBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeSTTS); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4DecodingTime2SampleBox), nil
+    }
+}
+
+func (v *Mp4SampleTableBox) Ctts() (*Mp4CompositionTime2SampleBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeCTTS); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4CompositionTime2SampleBox), nil
+    }
+}
+
+func (v *Mp4SampleTableBox) Stss() (*Mp4SyncSampleBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeSTSS); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4SyncSampleBox), nil
+    }
+}
+
+// Get the sample sizes, the stsz or stz2, whichever is present.
+func (v *Mp4SampleTableBox) Stsz() (SampleSizeBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeSTSZ); err == nil {
+        return box.(*Mp4SampleSizeBox), nil
+    }
+    if box, err := v.Get(SrsMp4BoxTypeSTZ2); err != nil {
+        return nil, fmt.Errorf("can't find stsz or stz2 in stbl")
+    } else {
+        return box.(*Mp4CompactSampleSizeBox), nil
+    }
+}
+
+// Get the chunk offsets, the stco or co64, whichever is present.
+func (v *Mp4SampleTableBox) Stco() (ChunkOffsetBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeSTCO); err == nil {
+        return box.(*Mp4ChunkOffsetBox), nil
+    }
+    if box, err := v.Get(SrsMp4BoxTypeCO64); err != nil {
+        return nil, fmt.Errorf("can't find stco or co64 in stbl")
+    } else {
+        return box.(*Mp4ChunkLargeOffsetBox), nil
+    }
+}
+
+func (v *Mp4SampleTableBox) Stsd() (*Mp4SampleDescritionBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeSTSD); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4SampleDescritionBox), nil
+    }
+}
+
+/**
+ * 8.5.2 Sample Description Box
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 43
+ */
+type Mp4SampleEntry struct {
+    Mp4Box
+    Reserved [6]uint8
+    DataReferenceIndex uint16
+}
+
+func NewMp4SampleEntry() *Mp4SampleEntry {
+    v := &Mp4SampleEntry{
+        Reserved: [6]uint8{},
+    }
+    return v
+}
+
+func (v *Mp4SampleEntry) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4SampleEntry) Entry() *Mp4SampleEntry {
+    return v
+}
+
+func (v *Mp4SampleEntry) NbHeader() int {
+    return v.Mp4Box.NbHeader() + 6 + 2
+}
+
+func (v *Mp4SampleEntry) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Read(r, v.Reserved[:]); err != nil {
+        ol.E(nil, fmt.Sprintf("read sample entry reserved failed, err is %v", err))
+        return
+    }
+    if err = v.Read(r, &v.DataReferenceIndex); err != nil {
+        ol.E(nil, fmt.Sprintf("read sample entry data ref index failed, err is %v", err))
+        return
+    }
+    ol.T(nil, fmt.Sprintf("decode sample entry success, entry:%+v", v))
+    return
+}
+
+func (v *Mp4SampleEntry) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4Box.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.Reserved, v.DataReferenceIndex); err != nil {
+        ol.E(nil, fmt.Sprintf("write sample entry failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 8.5.2 Sample Description Box (avc1)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 44
+ */
+type Mp4VisualSampleEntry struct {
+    Mp4SampleEntry
+    PreDefined0     uint16
+    Reserved0       uint16
+    PreDefined1     [3]uint32
+    Width           uint16
+    Height          uint16
+    HorizResolution uint32
+    VertResolution  uint32
+    Reserved1       uint32
+    FrameCount      uint16
+    CompressorName  []uint8
+    Depth           uint16
+    PreDefined2     int16
+}
+
+func NewMp4VisualSampleEntry() *Mp4VisualSampleEntry {
+    v := &Mp4VisualSampleEntry{
+        PreDefined1: [3]uint32{},
+        FrameCount: 1,
+        HorizResolution: 0x00480000,
+        VertResolution: 0x00480000,
+        Depth: 0x0018,
+        PreDefined2: -1,
+    }
+    v.CompressorName = make([]uint8, 32)
+    return v
+}
+
+func (v *Mp4VisualSampleEntry) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4SampleEntry.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.PreDefined0); err != nil {
+        ol.E(nil, fmt.Sprintf("read avc1 predefined failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Reserved0); err != nil {
+        ol.E(nil, fmt.Sprintf("read avc1 reserved failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < len(v.PreDefined1); i ++ {
+        if err = v.Read(r, &v.PreDefined1[i]); err != nil {
+            ol.E(nil, fmt.Sprintf("read avc1 predefined %d failed, err is %v", i, err))
+            return
+        }
+    }
+
+    if err = v.Read(r, &v.Width); err != nil {
+        ol.E(nil, fmt.Sprintf("read avc1 width failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Height); err != nil {
+        ol.E(nil, fmt.Sprintf("read avc1 height failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.HorizResolution); err != nil {
+        ol.E(nil, fmt.Sprintf("read avc1 horizon resolution failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.VertResolution); err != nil {
+        ol.E(nil, fmt.Sprintf("read avc1 vertical resolution failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Reserved1); err != nil {
+        ol.E(nil, fmt.Sprintf("read avc1 reserved failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.FrameCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read avc1 frame count failed, err is %v", err))
+        return
+    }
+    ol.T(nil, fmt.Sprintf("after read frame count, usedSize=%v", v.UsedSize))
+
+    if err = v.Read(r, v.CompressorName); err != nil {
+        ol.E(nil, fmt.Sprintf("read avc1 compressor name failed, err is %v", err))
+        return
+    }
+    ol.T(nil, fmt.Sprintf("after read CompressorName, usedSize=%v", v.UsedSize))
+
+    if err = v.Read(r, &v.Depth); err != nil {
+        ol.E(nil, fmt.Sprintf("read avc1 depth failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.PreDefined2); err != nil {
+        ol.E(nil, fmt.Sprintf("read avc1 predefined failed, err is %v", err))
+        return
+    }
+    ol.T(nil, fmt.Sprintf("decode avc1 succes, data:%+v, left:%v", v, v.left()))
+    return
+}
+
+func (v *Mp4VisualSampleEntry) NbHeader() int {
+    return v.Mp4SampleEntry.NbHeader() + 2 + 2 + 12 + 2 + 2 + 4 + 4 + 4 + 2 + len(v.CompressorName) + 2 + 2
+}
+
+func (v *Mp4VisualSampleEntry) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4SampleEntry.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.PreDefined0, v.Reserved0, v.PreDefined1, v.Width, v.Height, v.HorizResolution, v.VertResolution,
+        v.Reserved1, v.FrameCount, v.CompressorName, v.Depth, v.PreDefined2); err != nil {
+        ol.E(nil, fmt.Sprintf("write avc1 failed, err is %v", err))
+        return
+    }
+    return
+}
+
+// Get the compressor name, which is formatted in a fixed 32-byte field, with the first
+// byte set to the number of bytes to be displayed.
+func (v *Mp4VisualSampleEntry) Compressor() string {
+    if len(v.CompressorName) == 0 {
+        return ""
+    }
+    n := int(v.CompressorName[0])
+    if n > len(v.CompressorName) - 1 {
+        n = len(v.CompressorName) - 1
+    }
+    return string(v.CompressorName[1:1 + n])
+}
+
+func (v *Mp4VisualSampleEntry) Hvcc() (*Mp4HvccBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeHVCC); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4HvccBox), nil
+    }
+}
+
+func (v *Mp4VisualSampleEntry) Av1c() (*Mp4Av1cBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeAV1C); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4Av1cBox), nil
+    }
+}
+
+func (v *Mp4VisualSampleEntry) Vpcc() (*Mp4VpccBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeVPCC); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4VpccBox), nil
+    }
+}
+
+func (v *Mp4VisualSampleEntry) Avcc() (*Mp4AvccBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeAVCC); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4AvccBox), nil
+    }
+}
+
+/**
+ * 5.3.4 AVC Video Stream Definition (avcC)
+ * ISO_IEC_14496-15-AVC-format-2012.pdf, page 19
+ */
+type Mp4AvccBox struct {
+    Mp4Box
+    NbConfig int
+    AvcConfig []uint8
+
+    // The AVCDecoderConfigurationRecord decoded from AvcConfig.
+    ConfigurationVersion uint8
+    AvcProfileIndication uint8
+    ProfileCompatibility uint8
+    AvcLevelIndication uint8
+    LengthSizeMinusOne uint8
+    SequenceParameterSets [][]uint8
+    PictureParameterSets [][]uint8
+    // The extensions for the high profiles, only when HighProfileExtension is true.
+    HighProfileExtension bool
+    ChromaFormat uint8
+    BitDepthLumaMinus8 uint8
+    BitDepthChromaMinus8 uint8
+    SequenceParameterSetExts [][]uint8
+}
+
+func (v *Mp4AvccBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4AvccBox) DecodeHeader(r io.Reader) (err error) {
+    v.NbConfig = int(v.left())
+    v.AvcConfig = make([]uint8, v.NbConfig)
+    if err = v.Read(r, v.AvcConfig); err != nil {
+        ol.E(nil, fmt.Sprintf("read avcc config failed, err is %v", err))
+        return
+    }
+
+    // Tolerate the corrupt record, which is kept as is.
+    if err := v.decodeConfig(bytes.NewReader(v.AvcConfig)); err != nil {
+        ol.W(nil, fmt.Sprintf("ignore avcc config, err is %v", err))
+    }
+    ol.T(nil, fmt.Sprintf("read avcc box success, nv config=%v, profile=%v, level=%v, sps=%v, pps=%v", v.NbConfig,
+        v.AvcProfileIndication, v.AvcLevelIndication, len(v.SequenceParameterSets), len(v.PictureParameterSets)))
+    return
+}
+
+// Decode the AVCDecoderConfigurationRecord, 5.3.3.1.1 Syntax.
+func (v *Mp4AvccBox) decodeConfig(r *bytes.Reader) (err error) {
+    var data [5]uint8
+    if _, err = io.ReadFull(r, data[:]); err != nil {
+        return
+    }
+    v.ConfigurationVersion, v.AvcProfileIndication, v.ProfileCompatibility, v.AvcLevelIndication = data[0], data[1], data[2], data[3]
+    v.LengthSizeMinusOne = data[4] & 0x03
+
+    var nb uint8
+    if nb, err = r.ReadByte(); err != nil {
+        return
+    }
+    if v.SequenceParameterSets, err = readAvccNalus(r, int(nb & 0x1f), SrsAvcNaluTypeSPS); err != nil {
+        return
+    }
+    if nb, err = r.ReadByte(); err != nil {
+        return
+    }
+    if v.PictureParameterSets, err = readAvccNalus(r, int(nb), SrsAvcNaluTypePPS); err != nil {
+        return
+    }
+
+    // The extensions are for all profiles except Baseline, Main and Extended, for example, the High 4:4:4
+    // Predictive, and they are missing in some encoders, so it's optional.
+    switch v.AvcProfileIndication {
+    case SrsAvcProfileBaseline, SrsAvcProfileMain, SrsAvcProfileExtended:
+        return
+    }
+    if r.Len() < 4 {
+        return
+    }
+    var ext [4]uint8
+    if _, err = io.ReadFull(r, ext[:]); err != nil {
+        return
+    }
+    v.HighProfileExtension = true
+    v.ChromaFormat, v.BitDepthLumaMinus8, v.BitDepthChromaMinus8 = ext[0] & 0x03, ext[1] & 0x07, ext[2] & 0x07
+    v.SequenceParameterSetExts, err = readAvccNalus(r, int(ext[3]), SrsAvcNaluTypeSPSExt)
+    return
+}
+
+// Read nb NALUs of avcC, each is prefixed by 16 bits length.
+func readAvccNalus(r *bytes.Reader, nb int, naluType uint8) (nalus [][]uint8, err error) {
+    for i := 0; i < nb; i++ {
+        var size uint16
+        if err = binary.Read(r, binary.BigEndian, &size); err != nil {
+            return
+        }
+        nalu := make([]uint8, size)
+        if _, err = io.ReadFull(r, nalu); err != nil {
+            return
+        }
+        if len(nalu) > 0 && nalu[0] & 0x1f != naluType {
+            ol.W(nil, fmt.Sprintf("avcc nalu type %v, expect %v", nalu[0] & 0x1f, naluType))
+        }
+        nalus = append(nalus, nalu)
+    }
+    return
+}
+
+// Decode the first SPS.
+func (v *Mp4AvccBox) Sps() (*Mp4AvcSps, error) {
+    if len(v.SequenceParameterSets) == 0 {
+        return nil, fmt.Errorf("no sps in avcc")
+    }
+    return NewMp4AvcSps(v.SequenceParameterSets[0])
+}
+
+func (v *Mp4AvccBox) NbHeader() int {
+    return v.Mp4Box.NbHeader() + len(v.AvcConfig)
+}
+
+func (v *Mp4AvccBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4Box.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.AvcConfig); err != nil {
+        ol.E(nil, fmt.Sprintf("write avcc config failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 8.3.3.1 HEVC Decoder Configuration Record (hvcC)
+ * ISO_IEC_14496-15-AVC-format-2014.pdf, page 66
+ * The record is kept as stored in HevcConfig for encoding, and the fields are decoded from it.
+ */
+type Mp4HvccBox struct {
+    Mp4Box
+    HevcConfig []uint8
+
+    ConfigurationVersion uint8
+    GeneralProfileSpace uint8
+    GeneralTierFlag uint8
+    GeneralProfileIdc uint8
+    GeneralProfileCompatibilityFlags uint32
+    // The 48 bits of general_constraint_indicator_flags.
+    GeneralConstraintIndicatorFlags uint64
+    GeneralLevelIdc uint8
+    MinSpatialSegmentationIdc uint16
+    ParallelismType uint8
+    ChromaFormatIdc uint8
+    BitDepthLumaMinus8 uint8
+    BitDepthChromaMinus8 uint8
+    // The average frame rate in frames/(256 seconds), 0 for unspecified.
+    AvgFrameRate uint16
+    ConstantFrameRate uint8
+    NumTemporalLayers uint8
+    TemporalIdNested uint8
+    LengthSizeMinusOne uint8
+    Arrays []*Mp4HvccNaluArray
+}
+
+// The NALUs of the same type in hvcC, for example, the VPS, SPS and PPS.
+type Mp4HvccNaluArray struct {
+    ArrayCompleteness uint8
+    NaluType uint8
+    Nalus [][]uint8
+}
+
+func (v *Mp4HvccBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4HvccBox) DecodeHeader(r io.Reader) (err error) {
+    v.HevcConfig = make([]uint8, v.left())
+    if err = v.Read(r, v.HevcConfig); err != nil {
+        ol.E(nil, fmt.Sprintf("read hvcc config failed, err is %v", err))
+        return
+    }
+
+    if err = v.decodeConfig(bytes.NewReader(v.HevcConfig)); err != nil {
+        ol.E(nil, fmt.Sprintf("decode hvcc config failed, err is %v", err))
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode hvcc box success, profile=%v, level=%v, arrays=%v", v.GeneralProfileIdc, v.GeneralLevelIdc, len(v.Arrays)))
+    return
+}
+
+func (v *Mp4HvccBox) decodeConfig(r io.Reader) (err error) {
+    var data [23]uint8
+    if _, err = io.ReadFull(r, data[:]); err != nil {
+        return
+    }
+
+    v.ConfigurationVersion = data[0]
+    v.GeneralProfileSpace = data[1] >> 6
+    v.GeneralTierFlag = (data[1] >> 5) & 0x01
+    v.GeneralProfileIdc = data[1] & 0x1f
+    v.GeneralProfileCompatibilityFlags = binary.BigEndian.Uint32(data[2:6])
+    v.GeneralConstraintIndicatorFlags = uint64(binary.BigEndian.Uint16(data[6:8])) << 32 | uint64(binary.BigEndian.Uint32(data[8:12]))
+    v.GeneralLevelIdc = data[12]
+    v.MinSpatialSegmentationIdc = binary.BigEndian.Uint16(data[13:15]) & 0x0fff
+    v.ParallelismType = data[15] & 0x03
+    v.ChromaFormatIdc = data[16] & 0x03
+    v.BitDepthLumaMinus8 = data[17] & 0x07
+    v.BitDepthChromaMinus8 = data[18] & 0x07
+    v.AvgFrameRate = binary.BigEndian.Uint16(data[19:21])
+    v.ConstantFrameRate = data[21] >> 6
+    v.NumTemporalLayers = (data[21] >> 3) & 0x07
+    v.TemporalIdNested = (data[21] >> 2) & 0x01
+    v.LengthSizeMinusOne = data[21] & 0x03
+
+    v.Arrays = nil
+    for i := 0; i < int(data[22]); i++ {
+        var header [3]uint8
+        if _, err = io.ReadFull(r, header[:]); err != nil {
+            return
+        }
+        array := &Mp4HvccNaluArray{
+            ArrayCompleteness: header[0] >> 7,
+            NaluType: header[0] & 0x3f,
+        }
+
+        for j := 0; j < int(binary.BigEndian.Uint16(header[1:])); j++ {
+            var size uint16
+            if err = binary.Read(r, binary.BigEndian, &size); err != nil {
+                return
+            }
+            nalu := make([]uint8, size)
+            if _, err = io.ReadFull(r, nalu); err != nil {
+                return
+            }
+            array.Nalus = append(array.Nalus, nalu)
+        }
+        v.Arrays = append(v.Arrays, array)
+    }
+    return
+}
+
+func (v *Mp4HvccBox) NbHeader() int {
+    return v.Mp4Box.NbHeader() + len(v.HevcConfig)
+}
+
+func (v *Mp4HvccBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4Box.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.HevcConfig); err != nil {
+        ol.E(nil, fmt.Sprintf("write hvcc config failed, err is %v", err))
+        return
+    }
+    return
+}
+
+// Get the NALUs of type, for example, SrsHevcNaluTypeSPS.
+func (v *Mp4HvccBox) Nalus(naluType uint8) (nalus [][]uint8) {
+    for _, array := range v.Arrays {
+        if array.NaluType == naluType {
+            nalus = append(nalus, array.Nalus...)
+        }
+    }
+    return
+}
+
+func (v *Mp4HvccBox) Vps() [][]uint8 {
+    return v.Nalus(SrsHevcNaluTypeVPS)
+}
+
+func (v *Mp4HvccBox) Sps() [][]uint8 {
+    return v.Nalus(SrsHevcNaluTypeSPS)
+}
+
+func (v *Mp4HvccBox) Pps() [][]uint8 {
+    return v.Nalus(SrsHevcNaluTypePPS)
+}
+
+/**
+ * 2.3 AV1 Codec Configuration Box (av1C)
+ * av1-isobmff-v1.2.0.pdf, AV1 Codec ISO Media File Format Binding
+ */
+type Mp4Av1cBox struct {
+    Mp4Box
+    // The marker is 1 and version is 1.
+    Marker uint8
+    Version uint8
+    SeqProfile uint8
+    SeqLevelIdx0 uint8
+    SeqTier0 uint8
+    HighBitdepth uint8
+    TwelveBit uint8
+    Monochrome uint8
+    ChromaSubsamplingX uint8
+    ChromaSubsamplingY uint8
+    ChromaSamplePosition uint8
+    Reserved uint8
+    InitialPresentationDelayPresent uint8
+    // The initial_presentation_delay_minus_one when present, or reserved.
+    InitialPresentationDelayMinusOne uint8
+    // The OBUs of sequence header and metadata, in Low Overhead Bitstream Format.
+    ConfigOBUs []uint8
+}
+
+func (v *Mp4Av1cBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4Av1cBox) DecodeHeader(r io.Reader) (err error) {
+    data := make([]uint8, 4)
+    if err = v.Read(r, data); err != nil {
+        ol.E(nil, fmt.Sprintf("read av1c config failed, err is %v", err))
+        return
+    }
+
+    v.Marker, v.Version = data[0] >> 7, data[0] & 0x7f
+    v.SeqProfile, v.SeqLevelIdx0 = data[1] >> 5, data[1] & 0x1f
+    v.SeqTier0 = data[2] >> 7
+    v.HighBitdepth = (data[2] >> 6) & 0x01
+    v.TwelveBit = (data[2] >> 5) & 0x01
+    v.Monochrome = (data[2] >> 4) & 0x01
+    v.ChromaSubsamplingX = (data[2] >> 3) & 0x01
+    v.ChromaSubsamplingY = (data[2] >> 2) & 0x01
+    v.ChromaSamplePosition = data[2] & 0x03
+    v.Reserved = data[3] >> 5
+    v.InitialPresentationDelayPresent = (data[3] >> 4) & 0x01
+    v.InitialPresentationDelayMinusOne = data[3] & 0x0f
+
+    v.ConfigOBUs = make([]uint8, v.left())
+    if err = v.Read(r, v.ConfigOBUs); err != nil {
+        ol.E(nil, fmt.Sprintf("read av1c config obus failed, err is %v", err))
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode av1c box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4Av1cBox) NbHeader() int {
+    return v.Mp4Box.NbHeader() + 4 + len(v.ConfigOBUs)
+}
+
+func (v *Mp4Av1cBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4Box.EncodeHeader(w); err != nil {
+        return
+    }
+    data := []uint8{
+        v.Marker << 7 | v.Version & 0x7f,
+        v.SeqProfile << 5 | v.SeqLevelIdx0 & 0x1f,
+        v.SeqTier0 << 7 | (v.HighBitdepth & 0x01) << 6 | (v.TwelveBit & 0x01) << 5 | (v.Monochrome & 0x01) << 4 |
+            (v.ChromaSubsamplingX & 0x01) << 3 | (v.ChromaSubsamplingY & 0x01) << 2 | v.ChromaSamplePosition & 0x03,
+        v.Reserved << 5 | (v.InitialPresentationDelayPresent & 0x01) << 4 | v.InitialPresentationDelayMinusOne & 0x0f,
+    }
+    if err = v.Write(w, data, v.ConfigOBUs); err != nil {
+        ol.E(nil, fmt.Sprintf("write av1c config failed, err is %v", err))
+        return
+    }
+    return
+}
+
+// Get the bit depth, 8, 10 or 12.
+func (v *Mp4Av1cBox) BitDepth() int {
+    if v.SeqProfile == 2 && v.HighBitdepth != 0 && v.TwelveBit != 0 {
+        return 12
+    }
+    if v.HighBitdepth != 0 {
+        return 10
+    }
+    return 8
+}
+
+/**
+ * VP Codec Configuration Box (vpcC)
+ * VP-Codec-ISO-Media-File-Format-Binding-v1.0.pdf
+ * The fields are decoded for version 1, the payload of other versions is kept as is.
+ */
+type Mp4VpccBox struct {
+    Mp4FullBox
+    Profile uint8
+    Level uint8
+    BitDepth uint8
+    ChromaSubsampling uint8
+    VideoFullRangeFlag uint8
+    ColourPrimaries uint8
+    TransferCharacteristics uint8
+    MatrixCoefficients uint8
+    CodecInitializationData []uint8
+    // The payload of version other than 1, or the bytes after the fields of version 1.
+    Data []uint8
+}
+
+func (v *Mp4VpccBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4VpccBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if v.Version != 1 {
+        v.Data = make([]uint8, v.left())
+        if err = v.Read(r, v.Data); err != nil {
+            ol.E(nil, fmt.Sprintf("read vpcc data failed, err is %v", err))
+            return
+        }
+        ol.W(nil, fmt.Sprintf("ignore vpcc version %v", v.Version))
+        return
+    }
+
+    data := make([]uint8, 6)
+    if err = v.Read(r, data); err != nil {
+        ol.E(nil, fmt.Sprintf("read vpcc config failed, err is %v", err))
+        return
+    }
+    v.Profile, v.Level = data[0], data[1]
+    v.BitDepth, v.ChromaSubsampling, v.VideoFullRangeFlag = data[2] >> 4, (data[2] >> 1) & 0x07, data[2] & 0x01
+    v.ColourPrimaries, v.TransferCharacteristics, v.MatrixCoefficients = data[3], data[4], data[5]
+
+    var size uint16
+    if err = v.Read(r, &size); err != nil {
+        ol.E(nil, fmt.Sprintf("read vpcc codec initialization data size failed, err is %v", err))
+        return
+    }
+    v.CodecInitializationData = make([]uint8, size)
+    if err = v.Read(r, v.CodecInitializationData); err != nil {
+        ol.E(nil, fmt.Sprintf("read vpcc codec initialization data failed, err is %v", err))
+        return
+    }
+
+    if left := v.left(); left > 0 {
+        v.Data = make([]uint8, left)
+        if err = v.Read(r, v.Data); err != nil {
+            ol.E(nil, fmt.Sprintf("read vpcc extra %v bytes failed, err is %v", left, err))
+            return
+        }
+    }
+
+    ol.T(nil, fmt.Sprintf("decode vpcc box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4VpccBox) NbHeader() int {
+    if v.Version != 1 {
+        return v.Mp4FullBox.NbHeader() + len(v.Data)
+    }
+    return v.Mp4FullBox.NbHeader() + 6 + 2 + len(v.CodecInitializationData) + len(v.Data)
+}
+
+func (v *Mp4VpccBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    if v.Version != 1 {
+        if err = v.Write(w, v.Data); err != nil {
+            ol.E(nil, fmt.Sprintf("write vpcc data failed, err is %v", err))
+        }
+        return
+    }
+    if err = v.Write(w, v.Profile, v.Level, v.BitDepth << 4 | (v.ChromaSubsampling & 0x07) << 1 | v.VideoFullRangeFlag & 0x01,
+        v.ColourPrimaries, v.TransferCharacteristics, v.MatrixCoefficients,
+        uint16(len(v.CodecInitializationData)), v.CodecInitializationData, v.Data); err != nil {
+        ol.E(nil, fmt.Sprintf("write vpcc config failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 8.5.2 Sample Description Box (mp4a)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 45
+ * @remark For QuickTime, the reserved is the version, revision and vendor of sound sample description,
+ * the version 1 and 2 have extra fields, see qtff-2015.pdf, page 198.
+ */
+type Mp4AudioSampleEntry struct {
+    Mp4SampleEntry
+    Reserved0 uint64
+    ChannelCount uint16
+    SampleSize uint16
+    PreDefined0 uint16
+    Reserved1 uint16
+    SampleRate uint32
+
+    // The QuickTime sound sample description version 1.
+    SamplesPerPacket uint32
+    BytesPerPacket uint32
+    BytesPerFrame uint32
+    BytesPerSample uint32
+
+    // The QuickTime sound sample description version 2, the fields of version 0 are fixed values.
+    SizeOfStructOnly uint32
+    AudioSampleRate float64
+    NumAudioChannels uint32
+    Always7F000000 uint32
+    ConstBitsPerChannel uint32
+    FormatSpecificFlags uint32
+    ConstBytesPerAudioPacket uint32
+    ConstLPCMFramesPerAudioPacket uint32
+
+    // Whether QuickTime sound sample description, by the stsd of version 0, see SoundVersion.
+    quickTime bool
+}
+
+func (v *Mp4AudioSampleEntry) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4SampleEntry.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.Reserved0); err != nil {
+        ol.E(nil, fmt.Sprintf("read mp4a reserved failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.ChannelCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read mp4a channel count failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.SampleSize); err != nil {
+        ol.E(nil, fmt.Sprintf("read mp4a sample size failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.PreDefined0); err != nil {
+        ol.E(nil, fmt.Sprintf("read mp4a predefined failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.Reserved1); err != nil {
+        ol.E(nil, fmt.Sprintf("read mp4a reserved failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.SampleRate); err != nil {
+        ol.E(nil, fmt.Sprintf("read mp4a sample rate failed, err is %v", err))
+        return
+    }
+
+    switch v.SoundVersion() {
+    case 1:
+        for _, field := range []*uint32{&v.SamplesPerPacket, &v.BytesPerPacket, &v.BytesPerFrame, &v.BytesPerSample} {
+            if err = v.Read(r, field); err != nil {
+                ol.E(nil, fmt.Sprintf("read sound description v1 failed, err is %v", err))
+                return
+            }
+        }
+    case 2:
+        if err = v.Read(r, &v.SizeOfStructOnly); err != nil {
+            ol.E(nil, fmt.Sprintf("read sound description v2 size failed, err is %v", err))
+            return
+        }
+        if err = v.Read(r, &v.AudioSampleRate); err != nil {
+            ol.E(nil, fmt.Sprintf("read sound description v2 sample rate failed, err is %v", err))
+            return
+        }
+        for _, field := range []*uint32{&v.NumAudioChannels, &v.Always7F000000, &v.ConstBitsPerChannel, &v.FormatSpecificFlags,
+            &v.ConstBytesPerAudioPacket, &v.ConstLPCMFramesPerAudioPacket} {
+            if err = v.Read(r, field); err != nil {
+                ol.E(nil, fmt.Sprintf("read sound description v2 failed, err is %v", err))
+                return
+            }
+        }
+    }
+
+    ol.T(nil, fmt.Sprintf("decode mp4a succes, data:%+v %v", v, v.left()))
+    return
+}
+
+func (v *Mp4AudioSampleEntry) NbHeader() int {
+    size := v.Mp4SampleEntry.NbHeader() + 8 + 2 + 2 + 2 + 2 + 4
+    switch v.SoundVersion() {
+    case 1:
+        size += 16
+    case 2:
+        size += 36
+    }
+    return size
+}
+
+func (v *Mp4AudioSampleEntry) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4SampleEntry.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.Reserved0, v.ChannelCount, v.SampleSize, v.PreDefined0, v.Reserved1, v.SampleRate); err != nil {
+        ol.E(nil, fmt.Sprintf("write mp4a failed, err is %v", err))
+        return
+    }
+
+    switch v.SoundVersion() {
+    case 1:
+        err = v.Write(w, v.SamplesPerPacket, v.BytesPerPacket, v.BytesPerFrame, v.BytesPerSample)
+    case 2:
+        err = v.Write(w, v.SizeOfStructOnly, v.AudioSampleRate, v.NumAudioChannels, v.Always7F000000, v.ConstBitsPerChannel,
+            v.FormatSpecificFlags, v.ConstBytesPerAudioPacket, v.ConstLPCMFramesPerAudioPacket)
+    }
+    if err != nil {
+        ol.E(nil, fmt.Sprintf("write sound description v%v failed, err is %v", v.SoundVersion(), err))
+        return
+    }
+    return
+}
+
+// Get the version of QuickTime sound sample description, 0 for ISO, whose reserved is zero.
+// @remark The ISO AudioSampleEntryV1 in stsd of version 1 has no extra field, so it's version 0.
+func (v *Mp4AudioSampleEntry) SoundVersion() int {
+    if !v.quickTime {
+        return 0
+    }
+    return int(v.Reserved0 >> 48)
+}
+
+// Whether the uncompressed PCM, for example, ipcm or twos.
+func (v *Mp4AudioSampleEntry) IsPCM() bool {
+    switch v.BoxType {
+    case SrsMp4BoxTypeIPCM, SrsMp4BoxTypeFPCM, SrsMp4BoxTypeLPCM, SrsMp4BoxTypeTWOS, SrsMp4BoxTypeSOWT:
+        return true
+    }
+    return false
+}
+
+// Get the number of channels, by the sound description version 2 or the STREAMINFO of FLAC.
+func (v *Mp4AudioSampleEntry) Channels() int {
+    if v.SoundVersion() == 2 {
+        return int(v.NumAudioChannels)
+    }
+    if dfla, err := v.Dfla(); err == nil && dfla.StreamInfo != nil {
+        return int(dfla.StreamInfo.Channels)
+    }
+    return int(v.ChannelCount)
+}
+
+// Get the sample rate in Hz, by the sound description version 2 or the STREAMINFO of FLAC, which
+// may overflow the 16.16 of SampleRate.
+func (v *Mp4AudioSampleEntry) SamplingRate() uint32 {
+    if v.SoundVersion() == 2 {
+        return uint32(v.AudioSampleRate)
+    }
+    if dfla, err := v.Dfla(); err == nil && dfla.StreamInfo != nil {
+        return dfla.StreamInfo.SampleRate
+    }
+    return v.SampleRate >> 16
+}
+
+// Get the bits per sample, by the pcmC, the sound description version 2 or the STREAMINFO of FLAC.
+func (v *Mp4AudioSampleEntry) BitsPerSample() int {
+    if pcmc, err := v.Pcmc(); err == nil {
+        return int(pcmc.PcmSampleSize)
+    }
+    if v.SoundVersion() == 2 {
+        return int(v.ConstBitsPerChannel)
+    }
+    if dfla, err := v.Dfla(); err == nil && dfla.StreamInfo != nil {
+        return int(dfla.StreamInfo.BitsPerSample)
+    }
+    return int(v.SampleSize)
+}
+
+// Whether the PCM samples are little-endian, sowt is and twos is not, others by pcmC or the flags
+// of sound description version 2.
+func (v *Mp4AudioSampleEntry) LittleEndian() bool {
+    switch v.BoxType {
+    case SrsMp4BoxTypeSOWT:
+        return true
+    case SrsMp4BoxTypeTWOS:
+        return false
+    }
+    if pcmc, err := v.Pcmc(); err == nil {
+        return pcmc.FormatFlags & SRS_MP4_PCM_LITTLE_ENDIAN != 0
+    }
+    if v.SoundVersion() == 2 {
+        return v.FormatSpecificFlags & SRS_MP4_LPCM_BIG_ENDIAN == 0
+    }
+    return false
+}
+
+func (v *Mp4AudioSampleEntry) Esds() (*Mp4EsdsBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeESDS); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4EsdsBox), nil
+    }
+}
+
+func (v *Mp4AudioSampleEntry) Asc() (*Mp4DecoderSpecificInfo, error) {
+    if box, err := v.Esds(); err != nil {
+        return nil, err
+    } else {
+        return box.Asc()
+    }
+}
+
+func (v *Mp4AudioSampleEntry) Dops() (*Mp4OpusSpecificBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeDOPS); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4OpusSpecificBox), nil
+    }
+}
+
+func (v *Mp4AudioSampleEntry) Dac3() (*Mp4Ac3SpecificBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeDAC3); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4Ac3SpecificBox), nil
+    }
+}
+
+func (v *Mp4AudioSampleEntry) Dec3() (*Mp4Ec3SpecificBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeDEC3); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4Ec3SpecificBox), nil
+    }
+}
+
+func (v *Mp4AudioSampleEntry) Dfla() (*Mp4FlacSpecificBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeDFLA); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4FlacSpecificBox), nil
+    }
+}
+
+func (v *Mp4AudioSampleEntry) Pcmc() (*Mp4PcmConfigurationBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypePCMC); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4PcmConfigurationBox), nil
+    }
+}
+
+/**
+ * 4.3.2 Opus Specific Box (dOps)
+ * opus_in_isobmff.html, Encapsulation of Opus in ISO Base Media File Format
+ */
+type Mp4OpusSpecificBox struct {
+    Mp4Box
+    Version uint8
+    OutputChannelCount uint8
+    // The number of samples at 48 kHz to discard from the decoder output when starting playback.
+    PreSkip uint16
+    // The sample rate of the original input, not the playback rate, which is always 48 kHz.
+    InputSampleRate uint32
+    // The gain in Q7.8 dB to apply to the decoder output.
+    OutputGain int16
+    ChannelMappingFamily uint8
+    // The channel mapping table, only when the family is not 0.
+    StreamCount uint8
+    CoupledCount uint8
+    ChannelMapping []uint8
+}
+
+func (v *Mp4OpusSpecificBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4OpusSpecificBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Read(r, &v.Version); err != nil {
+        ol.E(nil, fmt.Sprintf("read dops version failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.OutputChannelCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read dops output channel count failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.PreSkip); err != nil {
+        ol.E(nil, fmt.Sprintf("read dops pre skip failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.InputSampleRate); err != nil {
+        ol.E(nil, fmt.Sprintf("read dops input sample rate failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.OutputGain); err != nil {
+        ol.E(nil, fmt.Sprintf("read dops output gain failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.ChannelMappingFamily); err != nil {
+        ol.E(nil, fmt.Sprintf("read dops channel mapping family failed, err is %v", err))
+        return
+    }
+
+    if v.ChannelMappingFamily != 0 {
+        if err = v.Read(r, &v.StreamCount); err != nil {
+            ol.E(nil, fmt.Sprintf("read dops stream count failed, err is %v", err))
+            return
+        }
+
+        if err = v.Read(r, &v.CoupledCount); err != nil {
+            ol.E(nil, fmt.Sprintf("read dops coupled count failed, err is %v", err))
+            return
+        }
+
+        v.ChannelMapping = make([]uint8, v.OutputChannelCount)
+        if err = v.Read(r, v.ChannelMapping); err != nil {
+            ol.E(nil, fmt.Sprintf("read dops channel mapping failed, err is %v", err))
+            return
+        }
+    }
+
+    ol.T(nil, fmt.Sprintf("decode dops box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4OpusSpecificBox) NbHeader() int {
+    size := v.Mp4Box.NbHeader() + 1 + 1 + 2 + 4 + 2 + 1
+    if v.ChannelMappingFamily != 0 {
+        size += 1 + 1 + len(v.ChannelMapping)
+    }
+    return size
+}
+
+func (v *Mp4OpusSpecificBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4Box.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.Version, v.OutputChannelCount, v.PreSkip, v.InputSampleRate, v.OutputGain, v.ChannelMappingFamily); err != nil {
+        ol.E(nil, fmt.Sprintf("write dops failed, err is %v", err))
+        return
+    }
+    if v.ChannelMappingFamily != 0 {
+        if err = v.Write(w, v.StreamCount, v.CoupledCount, v.ChannelMapping); err != nil {
+            ol.E(nil, fmt.Sprintf("write dops channel mapping failed, err is %v", err))
+            return
+        }
+    }
+    return
+}
+
+/**
+ * F.4 AC3SpecificBox (dac3)
+ * ETSI_TS_102_366_V1.4.1.pdf, page 202
+ */
+type Mp4Ac3SpecificBox struct {
+    Mp4Box
+    Fscod uint8
+    Bsid uint8
+    Bsmod uint8
+    Acmod uint8
+    Lfeon uint8
+    BitRateCode uint8
+    Reserved uint8
+}
+
+func (v *Mp4Ac3SpecificBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4Ac3SpecificBox) DecodeHeader(r io.Reader) (err error) {
+    data := make([]uint8, 3)
+    if err = v.Read(r, data); err != nil {
+        ol.E(nil, fmt.Sprintf("read dac3 failed, err is %v", err))
+        return
+    }
+
+    br := newBitReader(data)
+    v.Fscod, _ = br.read8(2)
+    v.Bsid, _ = br.read8(5)
+    v.Bsmod, _ = br.read8(3)
+    v.Acmod, _ = br.read8(3)
+    v.Lfeon, _ = br.read8(1)
+    v.BitRateCode, _ = br.read8(5)
+    v.Reserved, _ = br.read8(5)
+
+    ol.T(nil, fmt.Sprintf("decode dac3 box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4Ac3SpecificBox) NbHeader() int {
+    return v.Mp4Box.NbHeader() + 3
+}
+
+func (v *Mp4Ac3SpecificBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4Box.EncodeHeader(w); err != nil {
+        return
+    }
+    u := uint32(v.Fscod & 0x03) << 22 | uint32(v.Bsid & 0x1f) << 17 | uint32(v.Bsmod & 0x07) << 14 | uint32(v.Acmod & 0x07) << 11 |
+        uint32(v.Lfeon & 0x01) << 10 | uint32(v.BitRateCode & 0x1f) << 5 | uint32(v.Reserved & 0x1f)
+    if err = v.Write(w, []uint8{uint8(u >> 16), uint8(u >> 8), uint8(u)}); err != nil {
+        ol.E(nil, fmt.Sprintf("write dac3 failed, err is %v", err))
+        return
+    }
+    return
+}
+
+// Get the sample rate in Hz, by the fscod.
+func (v *Mp4Ac3SpecificBox) SampleRate() uint32 {
+    return ac3SampleRate(v.Fscod)
+}
+
+// Get the number of channels, including the LFE, by the acmod and lfeon.
+func (v *Mp4Ac3SpecificBox) ChannelCount() int {
+    return ac3ChannelCount(v.Acmod, v.Lfeon)
+}
+
+// Get the bitrate in bps, by the bit rate code, Table F.4.1.
+func (v *Mp4Ac3SpecificBox) Bitrate() uint32 {
+    rates := []uint32{32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 448, 512, 576, 640}
+    if int(v.BitRateCode) < len(rates) {
+        return rates[v.BitRateCode] * 1000
+    }
+    return 0
+}
+
+// The independent substream of E-AC-3, see Mp4Ec3SpecificBox.
+type Mp4Ec3Substream struct {
+    Fscod uint8 `json:"fscod"`
+    Bsid uint8 `json:"bsid"`
+    Asvc uint8 `json:"asvc"`
+    Bsmod uint8 `json:"bsmod"`
+    Acmod uint8 `json:"acmod"`
+    Lfeon uint8 `json:"lfeon"`
+    NumDepSub uint8 `json:"num_dep_sub"`
+    // The channel locations of the dependent substreams, only when NumDepSub is not 0.
+    ChanLoc uint16 `json:"chan_loc"`
+}
+
+/**
+ * F.6 EC3SpecificBox (dec3)
+ * ETSI_TS_102_366_V1.4.1.pdf, page 204
+ * The box is kept as stored in Data for encoding, and the fields are decoded from it.
+ */
+type Mp4Ec3SpecificBox struct {
+    Mp4Box
+    Data []uint8
+
+    // The data rate in kbps.
+    DataRate uint16
+    // The independent substreams, the num_ind_sub plus 1.
+    Substreams []*Mp4Ec3Substream
+    // The Dolby Atmos in Joint Object Coding, which is the optional extension.
+    FlagEc3ExtensionTypeA uint8
+    ComplexityIndexTypeA uint8
+}
+
+func (v *Mp4Ec3SpecificBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4Ec3SpecificBox) DecodeHeader(r io.Reader) (err error) {
+    v.Data = make([]uint8, v.left())
+    if err = v.Read(r, v.Data); err != nil {
+        ol.E(nil, fmt.Sprintf("read dec3 failed, err is %v", err))
+        return
+    }
+
+    if err = v.decodeData(newBitReader(v.Data)); err != nil {
+        ol.E(nil, fmt.Sprintf("decode dec3 failed, err is %v", err))
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode dec3 box success, data rate=%v, substreams=%v, joc=%v", v.DataRate, len(v.Substreams), v.FlagEc3ExtensionTypeA))
+    return
+}
+
+func (v *Mp4Ec3SpecificBox) decodeData(br *bitReader) (err error) {
+    var u uint32
+    if u, err = br.read(13); err != nil {
+        return
+    }
+    v.DataRate = uint16(u)
+
+    var numIndSub uint8
+    if numIndSub, err = br.read8(3); err != nil {
+        return
+    }
+
+    v.Substreams = nil
+    for i := 0; i <= int(numIndSub); i++ {
+        if br.left() < 24 {
+            return fmt.Errorf("no space for substream %v, left=%v", i, br.left())
+        }
+        ss := &Mp4Ec3Substream{}
+        ss.Fscod, _ = br.read8(2)
+        ss.Bsid, _ = br.read8(5)
+        br.read(1)
+        ss.Asvc, _ = br.read8(1)
+        ss.Bsmod, _ = br.read8(3)
+        ss.Acmod, _ = br.read8(3)
+        ss.Lfeon, _ = br.read8(1)
+        br.read(3)
+        ss.NumDepSub, _ = br.read8(4)
+        if ss.NumDepSub > 0 {
+            u, _ = br.read(9)
+            ss.ChanLoc = uint16(u)
+        } else {
+            br.read(1)
+        }
+        v.Substreams = append(v.Substreams, ss)
+    }
+
+    // The extension for Atmos is optional.
+    if br.left() >= 16 {
+        br.read(7)
+        v.FlagEc3ExtensionTypeA, _ = br.read8(1)
+        v.ComplexityIndexTypeA, _ = br.read8(8)
+    }
+    return
+}
+
+func (v *Mp4Ec3SpecificBox) NbHeader() int {
+    return v.Mp4Box.NbHeader() + len(v.Data)
+}
+
+func (v *Mp4Ec3SpecificBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4Box.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.Data); err != nil {
+        ol.E(nil, fmt.Sprintf("write dec3 failed, err is %v", err))
+        return
+    }
+    return
+}
+
+// Get the sample rate in Hz, by the fscod of the first independent substream.
+func (v *Mp4Ec3SpecificBox) SampleRate() uint32 {
+    if len(v.Substreams) == 0 {
+        return 0
+    }
+    return ac3SampleRate(v.Substreams[0].Fscod)
+}
+
+// Get the number of channels of the first independent substream, with its dependent substreams.
+func (v *Mp4Ec3SpecificBox) ChannelCount() int {
+    if len(v.Substreams) == 0 {
+        return 0
+    }
+    ss := v.Substreams[0]
+    n := ac3ChannelCount(ss.Acmod, ss.Lfeon)
+    // The chan_loc from the MSB, Table F.6.1, the pairs are Lc/Rc, Lrs/Rrs, Lsd/Rsd, Lw/Rw and Lvh/Rvh.
+    for i, channels := range []int{2, 2, 1, 1, 2, 2, 2, 1, 1} {
+        if ss.NumDepSub > 0 && ss.ChanLoc & (0x100 >> uint(i)) != 0 {
+            n += channels
+        }
+    }
+    return n
+}
+
+// Get the bitrate in bps, by the data rate.
+func (v *Mp4Ec3SpecificBox) Bitrate() uint32 {
+    return uint32(v.DataRate) * 1000
+}
+
+// Whether the stream carries Dolby Atmos in Joint Object Coding.
+func (v *Mp4Ec3SpecificBox) Atmos() bool {
+    return v.FlagEc3ExtensionTypeA != 0
+}
+
+// The metadata block of FLAC, see Mp4FlacSpecificBox.
+type Mp4FlacMetadataBlock struct {
+    LastMetadataBlockFlag uint8
+    BlockType uint8
+    Data []uint8
+}
+
+// The STREAMINFO metadata block of FLAC, see https://xiph.org/flac/format.html#metadata_block_streaminfo
+type Mp4FlacStreamInfo struct {
+    MinBlockSize uint16
+    MaxBlockSize uint16
+    MinFrameSize uint32
+    MaxFrameSize uint32
+    SampleRate uint32
+    Channels uint8
+    BitsPerSample uint8
+    // The total samples in stream, 0 for unknown.
+    TotalSamples uint64
+    Md5 []uint8
+}
+
+/**
+ * 3.3.2 FLAC Specific Box (dfLa)
+ * ISOBMFF-FLAC-mapping-v0.5.2.html, Encapsulation of FLAC in ISO Base Media File Format
+ * The metadata blocks of FLAC, the first one is the STREAMINFO.
+ */
+type Mp4FlacSpecificBox struct {
+    Mp4FullBox
+    Blocks []*Mp4FlacMetadataBlock
+    // The STREAMINFO decoded from the blocks, nil if not found.
+    StreamInfo *Mp4FlacStreamInfo
+}
+
+func (v *Mp4FlacSpecificBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4FlacSpecificBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    for v.left() > 0 {
+        var header uint32
+        if err = v.Read(r, &header); err != nil {
+            ol.E(nil, fmt.Sprintf("read dfla metadata block header failed, err is %v", err))
+            return
+        }
+
+        block := &Mp4FlacMetadataBlock{
+            LastMetadataBlockFlag: uint8(header >> 31),
+            BlockType: uint8(header >> 24) & 0x7f,
+        }
+        if uint64(header & 0xffffff) > v.left() {
+            err = fmt.Errorf("metadata block length %v overflow, left=%v", header & 0xffffff, v.left())
+            ol.E(nil, fmt.Sprintf("read dfla failed, err is %v", err))
+            return
+        }
+        block.Data = make([]uint8, header & 0xffffff)
+        if err = v.Read(r, block.Data); err != nil {
+            ol.E(nil, fmt.Sprintf("read dfla metadata block failed, err is %v", err))
+            return
+        }
+        v.Blocks = append(v.Blocks, block)
+
+        if block.BlockType == SRS_MP4_FLAC_METADATA_STREAMINFO && v.StreamInfo == nil {
+            if v.StreamInfo, err = decodeFlacStreamInfo(block.Data); err != nil {
+                ol.E(nil, fmt.Sprintf("decode dfla streaminfo failed, err is %v", err))
+                return
+            }
+        }
+        if block.LastMetadataBlockFlag != 0 {
+            break
+        }
+    }
+
+    ol.T(nil, fmt.Sprintf("decode dfla box success, blocks=%v, streaminfo:%+v", len(v.Blocks), v.StreamInfo))
+    return
+}
+
+func decodeFlacStreamInfo(data []uint8) (v *Mp4FlacStreamInfo, err error) {
+    if len(data) < 34 {
+        return nil, fmt.Errorf("streaminfo requires 34 bytes, actual %v", len(data))
+    }
+
+    br := newBitReader(data)
+    v = &Mp4FlacStreamInfo{}
+    var u uint32
+    u, _ = br.read(16)
+    v.MinBlockSize = uint16(u)
+    u, _ = br.read(16)
+    v.MaxBlockSize = uint16(u)
+    v.MinFrameSize, _ = br.read(24)
+    v.MaxFrameSize, _ = br.read(24)
+    v.SampleRate, _ = br.read(20)
+    u, _ = br.read(3)
+    v.Channels = uint8(u) + 1
+    u, _ = br.read(5)
+    v.BitsPerSample = uint8(u) + 1
+    hi, _ := br.read(4)
+    lo, _ := br.read(32)
+    v.TotalSamples = uint64(hi) << 32 | uint64(lo)
+    v.Md5 = data[18:34]
+    return
+}
+
+func (v *Mp4FlacSpecificBox) NbHeader() int {
+    size := v.Mp4FullBox.NbHeader()
+    for _, block := range v.Blocks {
+        size += 4 + len(block.Data)
+    }
+    return size
+}
+
+func (v *Mp4FlacSpecificBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    for _, block := range v.Blocks {
+        header := uint32(block.LastMetadataBlockFlag & 0x01) << 31 | uint32(block.BlockType & 0x7f) << 24 | uint32(len(block.Data))
+        if err = v.Write(w, header, block.Data); err != nil {
+            ol.E(nil, fmt.Sprintf("write dfla metadata block failed, err is %v", err))
+            return
+        }
+    }
+    return
+}
+
+/**
+ * 5.1 PCM configuration box (pcmC)
+ * ISO_IEC_23003-5-2020.pdf, page 5
+ */
+type Mp4PcmConfigurationBox struct {
+    Mp4FullBox
+    // The bit 0 is set for little-endian, see SRS_MP4_PCM_LITTLE_ENDIAN.
+    FormatFlags uint8
+    PcmSampleSize uint8
+}
+
+func (v *Mp4PcmConfigurationBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4PcmConfigurationBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.FormatFlags); err != nil {
+        ol.E(nil, fmt.Sprintf("read pcmc format flags failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.PcmSampleSize); err != nil {
+        ol.E(nil, fmt.Sprintf("read pcmc sample size failed, err is %v", err))
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode pcmc box success, box:%+v", v))
+    return
+}
+
+func (v *Mp4PcmConfigurationBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 1 + 1
+}
+
+func (v *Mp4PcmConfigurationBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, v.FormatFlags, v.PcmSampleSize); err != nil {
+        ol.E(nil, fmt.Sprintf("write pcmc failed, err is %v", err))
+        return
+    }
+    return
+}
+
+// Get the sample rate in Hz of AC-3, by the fscod, Table F.4.1.
+func ac3SampleRate(fscod uint8) uint32 {
+    switch fscod {
+    case 0:
+        return 48000
+    case 1:
+        return 44100
+    case 2:
+        return 32000
+    }
+    return 0
+}
+
+// Get the number of channels of AC-3, by the audio coding mode and the LFE, Table 4.3.
+func ac3ChannelCount(acmod, lfeon uint8) int {
+    channels := []int{2, 1, 2, 3, 3, 4, 4, 5}
+    return channels[acmod & 0x07] + int(lfeon & 0x01)
+}
+
+/**
+ * 7.2.2.2 BaseDescriptor
+ * ISO_IEC_14496-1-System-2010.pdf, page 32
+ */
+type Mp4BaseDescriptor struct {
+               // The values of the class tags are
+               // defined in Table 2. As an expandable class the size of each class instance in bytes is encoded and accessible
+               // through the instance variable sizeOfInstance (see 8.3.3).
+    tag uint8 // bit(8)
+               // The decoded or encoded variant length.
+    vlen int32 // bit(28)
+
+    total int32
+
+    usedSize int32
+    // The bytes to the end of descriptor which are not decoded, kept for encoding.
+    extra []uint8
+}
+
+// Decode the tag and size of descriptor, which is in the parent of bytes, for example, the left bytes
+// of the esds box or the container descriptor.
+func (v *Mp4BaseDescriptor) decodeHeader(r io.Reader, parent int32) (err error) {
+    if err = binary.Read(r, binary.BigEndian, &v.tag); err != nil {
+        ol.E(nil, fmt.Sprintf("read desc tag failed, err is %v", err))
+        return
+    }
+    v.total += 1
+
+    var vsize uint8
+    var length int32
+    for {
+        if err = binary.Read(r, binary.BigEndian, &vsize); err != nil {
+            ol.E(nil, fmt.Sprintf("read desc 1byte size failed, err is %v", err))
+            return
+        }
+        length = (length << 7) | int32(vsize & 0x7f)
+        v.total += 1
+
+        if (vsize & 0x80 ) != 0x80 {
+            break
+        }
+        // The sizeOfInstance is at most 4 bytes, bit(28), see 8.3.3 Expandable classes.
+        if v.total >= 5 {
+            return fmt.Errorf("desc tag %v size overflow 4 bytes", v.tag)
+        }
+    }
+    v.vlen = length
+    v.total += length
+
+    if v.total > parent {
+        return fmt.Errorf("desc tag %v size %v overflow the parent %v", v.tag, v.total, parent)
+    }
+    return
+}
+
+func (v *Mp4BaseDescriptor) Read(r io.Reader, data interface{}) (err error) {
+    if err = binary.Read(r, binary.BigEndian, data); err != nil {
+        return
+    }
+    v.usedSize += int32(uint64DataSize(data))
+    return
+}
+
+func (v *Mp4BaseDescriptor) totalSize() int32 {
+    return v.total
+}
+
+func (v *Mp4BaseDescriptor) left() int32 {
+    return v.vlen - v.usedSize
+}
+
+// Read the bytes to the end of descriptor, for example, the optional descriptors we don't know.
+func (v *Mp4BaseDescriptor) decodeExtra(r io.Reader) (err error) {
+    if v.left() <= 0 {
+        return
+    }
+    v.extra = make([]uint8, v.left())
+    if err = v.Read(r, v.extra); err != nil {
+        ol.E(nil, fmt.Sprintf("read desc extra %v bytes failed, err is %v", len(v.extra), err))
+        return
+    }
+    return
+}
+
+// Get the number of bytes to encode the size of payload, keep the decoded one, which maybe padded,
+// for example, 0x80 0x80 0x80 0x22, so the encoded descriptor is identical.
+func (v *Mp4BaseDescriptor) nbLength(payload int32) int32 {
+    nb := int32(1)
+    for l := payload >> 7; l > 0; l >>= 7 {
+        nb ++
+    }
+    if decoded := v.total - 1 - v.vlen; v.total > 0 && decoded > nb {
+        nb = decoded
+    }
+    return nb
+}
+
+// Encode the tag and the size of payload, the tag is the decoded one or the default one.
+func (v *Mp4BaseDescriptor) encodeHeader(w io.Writer, tag uint8, payload int32) (err error) {
+    if v.tag != SrsMp4ESTagESforbidden {
+        tag = v.tag
+    }
+
+    b := []uint8{tag}
+    for i := v.nbLength(payload) - 1; i >= 0; i-- {
+        c := uint8((payload >> (7 * uint(i))) & 0x7f)
+        if i > 0 {
+            c |= 0x80
+        }
+        b = append(b, c)
+    }
+
+    if _, err = w.Write(b); err != nil {
+        ol.E(nil, fmt.Sprintf("write desc tag and size failed, err is %v", err))
+        return
+    }
+    return
+}
+
+func (v *Mp4BaseDescriptor) nbBytes(payload int32) int32 {
+    return 1 + v.nbLength(payload) + payload
+}
+
+/**
+ * 7.2.6.7 DecoderSpecificInfo
+ * ISO_IEC_14496-1-System-2010.pdf, page 51
+ */
+type Mp4DecoderSpecificInfo struct {
+    Mp4BaseDescriptor
+    Asc []uint8
+}
+
+func NewMp4DecoderSpecificInfo() *Mp4DecoderSpecificInfo {
+    v := &Mp4DecoderSpecificInfo{
+        Asc: []uint8{},
+    }
+    return v
+}
+
+// Decode the Asc as the AudioSpecificConfig of AAC.
+func (v *Mp4DecoderSpecificInfo) AacConfig() (*Mp4AacConfig, error) {
+    if v == nil || len(v.Asc) == 0 {
+        return nil, fmt.Errorf("no asc")
+    }
+    return NewMp4AacConfig(v.Asc)
+}
+
+func (v *Mp4DecoderSpecificInfo) decode(r io.Reader, parent int32) (err error) {
+    if err = v.Mp4BaseDescriptor.decodeHeader(r, parent); err != nil {
+        return
+    }
+
+    v.Asc = make([]uint8, v.vlen)
+    if err = v.Read(r, v.Asc); err != nil {
+        ol.E(nil, fmt.Sprintf("read DecoderSpecificInfo asc failed, err is %v", err))
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode specificInfo:asc:%+v", v.Asc))
+    return
+}
+
+func (v *Mp4DecoderSpecificInfo) nbPayload() int32 {
+    return int32(len(v.Asc))
+}
+
+func (v *Mp4DecoderSpecificInfo) encode(w io.Writer) (err error) {
+    if err = v.encodeHeader(w, SrsMp4ESTagESDecSpecificInfoTag, v.nbPayload()); err != nil {
+        return
+    }
+    if _, err = w.Write(v.Asc); err != nil {
+        ol.E(nil, fmt.Sprintf("write DecoderSpecificInfo asc failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 7.2.6.6 DecoderConfigDescriptor
+ * ISO_IEC_14496-1-System-2010.pdf, page 48
+ */
+type Mp4DecoderConfigDescriptor struct {
+    Mp4BaseDescriptor
+
+                                             // an indication of the object or scene description type that needs to be supported
+                                             // by the decoder for this elementary stream as per Table 5.
+    objectTypeIndication uint8 // bit(8)
+    streamType uint8 // bit(6)
+    upStream uint8 // bit(1)
+    reserved uint8 // bit(1)
+    bufferSizeDB uint32 // bit(24)
+    maxBitrate uint32
+    avgBitrate uint32
+    descSpecificInfo *Mp4DecoderSpecificInfo // optional.
+}
+
+func NewMp4DecoderConfigDescriptor() *Mp4DecoderConfigDescriptor {
+    v := &Mp4DecoderConfigDescriptor {
+        descSpecificInfo: &Mp4DecoderSpecificInfo{},
+    }
+    return v
+}
+
+func (v *Mp4DecoderConfigDescriptor) decode(r io.Reader, parent int32) (err error) {
+    if err = v.Mp4BaseDescriptor.decodeHeader(r, parent); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.objectTypeIndication); err != nil {
+        ol.E(nil, fmt.Sprintf("read DecoderConfigDescriptor objectTypeIndication failed, err is %v", err))
+        return
+    }
+
+    var data uint8
+    if err = v.Read(r, &data); err != nil {
+        ol.E(nil, fmt.Sprintf("read DecoderConfigDescriptor data failed, err is %v", err))
+        return
+    }
+    v.upStream = (data >> 1) & 0x01
+    v.streamType = (data >> 2) & 0x3f
+    v.reserved = data & 0x01
+
+    tmp := make([]byte, 3)
+    if _, err = io.ReadFull(r, tmp); err != nil {
+        ol.E(nil, fmt.Sprintf("read DecoderConfigDescriptor bufferSizeDB failed, err is %v", err))
+        return
+    }
+    v.bufferSizeDB = Bytes3ToUint32(tmp)
+    v.usedSize += 3
+
+    if err = v.Read(r, &v.maxBitrate); err != nil {
+        ol.E(nil, fmt.Sprintf("read DecoderConfigDescriptor maxBitrate failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.avgBitrate); err != nil {
+        ol.E(nil, fmt.Sprintf("read DecoderConfigDescriptor avgBitrate failed, err is %v", err))
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("after decode DecoderConfigDescriptor, left:%v", v.left()))
+    if v.left() > 0 {
+        if err = v.descSpecificInfo.decode(r, v.left()); err != nil {
+            ol.E(nil, fmt.Sprintf("decode descSpecificInfo failed, err is %v", err))
+            return
+        }
+        v.usedSize += v.descSpecificInfo.total
+    }
+
+    if err = v.decodeExtra(r); err != nil {
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode config desc:%+v", v))
+    return
+}
+
+// Whether there is the optional DecoderSpecificInfo.
+func (v *Mp4DecoderConfigDescriptor) hasSpecificInfo() bool {
+    dsi := v.descSpecificInfo
+    return dsi != nil && (dsi.tag != SrsMp4ESTagESforbidden || len(dsi.Asc) > 0)
+}
+
+func (v *Mp4DecoderConfigDescriptor) nbPayload() int32 {
+    size := int32(1 + 1 + 3 + 4 + 4)
+    if v.hasSpecificInfo() {
+        size += v.descSpecificInfo.nbBytes(v.descSpecificInfo.nbPayload())
+    }
+    return size + int32(len(v.extra))
+}
+
+func (v *Mp4DecoderConfigDescriptor) encode(w io.Writer) (err error) {
+    if err = v.encodeHeader(w, SrsMp4ESTagESDecoderConfigDescrTag, v.nbPayload()); err != nil {
+        return
+    }
+
+    data := (v.streamType & 0x3f) << 2 | (v.upStream & 0x01) << 1 | v.reserved & 0x01
+    b := []uint8{v.objectTypeIndication, data, uint8(v.bufferSizeDB >> 16), uint8(v.bufferSizeDB >> 8), uint8(v.bufferSizeDB)}
+    if err = binary.Write(w, binary.BigEndian, b); err == nil {
+        err = binary.Write(w, binary.BigEndian, []uint32{v.maxBitrate, v.avgBitrate})
+    }
+    if err != nil {
+        ol.E(nil, fmt.Sprintf("write DecoderConfigDescriptor failed, err is %v", err))
+        return
+    }
+
+    if v.hasSpecificInfo() {
+        if err = v.descSpecificInfo.encode(w); err != nil {
+            return
+        }
+    }
+
+    if _, err = w.Write(v.extra); err != nil {
+        ol.E(nil, fmt.Sprintf("write DecoderConfigDescriptor extra failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 7.3.2.3 SL Packet Header Configuration
+ * ISO_IEC_14496-1-System-2010.pdf, page 92
+ */
+type Mp4SLConfigDescriptor struct {
+    Mp4BaseDescriptor
+    predefined uint8
+}
+
+func (v *Mp4SLConfigDescriptor) decode(r io.Reader, parent int32) (err error) {
+    if err = v.Mp4BaseDescriptor.decodeHeader(r, parent); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.predefined); err != nil {
+        ol.E(nil, fmt.Sprintf("read SL predefined failed, err is %v", err))
+        return
+    }
+
+    // The fields for custom predefined, which are kept as is.
+    if err = v.decodeExtra(r); err != nil {
+        return
+    }
+    ol.T(nil, fmt.Sprintf("decde sl:predefined:%v", v.predefined))
+    return
+}
+
+func (v *Mp4SLConfigDescriptor) nbPayload() int32 {
+    return 1 + int32(len(v.extra))
+}
+
+func (v *Mp4SLConfigDescriptor) encode(w io.Writer) (err error) {
+    if err = v.encodeHeader(w, SrsMp4ESTagESSLConfigDescrTag, v.nbPayload()); err != nil {
+        return
+    }
+    if _, err = w.Write(append([]uint8{v.predefined}, v.extra...)); err != nil {
+        ol.E(nil, fmt.Sprintf("write SL predefined failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 7.2.6.5 ES_Descriptor
+ * ISO_IEC_14496-1-System-2010.pdf, page 47
+ */
+type Mp4ES_Descriptor struct {
+    Mp4BaseDescriptor
+
+    ES_ID uint16
+    streamDependenceFlag uint8 // bit(1)
+    URL_Flag uint8 // bit(1)
+    OCRstreamFlag uint8 // bit(1)
+    streamPriority uint8 // bit(5)
+                               // if (streamDependenceFlag)
+    dependsOn_ES_ID uint16
+                               // if (URL_Flag)
+    URLlength uint8
+    URLstring []uint8
+                               // if (OCRstreamFlag)
+    OCR_ES_Id uint16
+
+    decConfigDescr *Mp4DecoderConfigDescriptor
+    slConfigDescr *Mp4SLConfigDescriptor
+}
+
+func NewMp4ES_Descriptor() *Mp4ES_Descriptor {
+    v := &Mp4ES_Descriptor{
+        decConfigDescr: NewMp4DecoderConfigDescriptor(),
+        slConfigDescr: &Mp4SLConfigDescriptor{},
+    }
+    return v
+}
+
+func (v *Mp4ES_Descriptor) decode(r io.Reader, parent int32) (err error) {
+    if err = v.Mp4BaseDescriptor.decodeHeader(r, parent); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.ES_ID); err != nil {
+        ol.E(nil, fmt.Sprintf("read ES_Descriptor ES_ID failed, err is %v", err))
+        return
+    }
+
+    var data uint8
+    if err = v.Read(r, &data); err != nil {
+        ol.E(nil, fmt.Sprintf("read ES_Descriptor data failed, err is %v", err))
+        return
+    }
+    v.streamPriority = data & 0x1f
+    v.streamDependenceFlag = (data >> 7) & 0x01
+    v.URL_Flag = (data >> 6) & 0x01
+    v.OCRstreamFlag = (data >> 5) & 0x01
+
+    if v.streamDependenceFlag == 0x01 {
+        if err = v.Read(r, &v.dependsOn_ES_ID); err != nil {
+            ol.E(nil, fmt.Sprintf("read ES_Descriptor dependsOn_ES_ID failed, err is %v", err))
+            return
+        }
+    }
+
+    if v.URL_Flag == 0x01 {
+        if err = v.Read(r, &v.URLlength); err != nil {
+            ol.E(nil, fmt.Sprintf("read ES_Descriptor URLLength failed, err is %v", err))
+            return
+        }
+
+        v.URLstring = make([]uint8, v.URLlength)
+        if err = v.Read(r, v.URLstring); err != nil {
+            ol.E(nil, fmt.Sprintf("read ES_Descriptor URLstring failed, err is %v", err))
+            return
+        }
+    }
+
+    if v.OCRstreamFlag == 0x01 {
+        if err = v.Read(r, &v.OCR_ES_Id); err != nil {
+            ol.E(nil, fmt.Sprintf("read ES_Descriptor OCR_ES_Id failed, err is %v", err))
+            return
+        }
+    }
+
+    if err = v.decConfigDescr.decode(r, v.left()); err != nil {
+        ol.E(nil, fmt.Sprintf("decode ES_Descriptor decConfigDescr failed, err is %v", err))
+        return
+    }
+    v.usedSize += v.decConfigDescr.total
+
+    if err = v.slConfigDescr.decode(r, v.left()); err != nil {
+        ol.E(nil, fmt.Sprintf("decode ES_Descriptor slConfigDescr failed, err is %v", err))
+        return
+    }
+    v.usedSize += v.slConfigDescr.total
+
+    if err = v.decodeExtra(r); err != nil {
+        return
+    }
+
+    ol.T(nil, fmt.Sprintf("decode ES_Descriptor:%+v", v))
+    return
+}
+
+func (v *Mp4ES_Descriptor) nbPayload() int32 {
+    size := int32(2 + 1)
+    if v.streamDependenceFlag == 0x01 {
+        size += 2
+    }
+    if v.URL_Flag == 0x01 {
+        size += 1 + int32(len(v.URLstring))
+    }
+    if v.OCRstreamFlag == 0x01 {
+        size += 2
+    }
+    size += v.decConfigDescr.nbBytes(v.decConfigDescr.nbPayload())
+    size += v.slConfigDescr.nbBytes(v.slConfigDescr.nbPayload())
+    return size + int32(len(v.extra))
+}
+
+func (v *Mp4ES_Descriptor) encode(w io.Writer) (err error) {
+    if err = v.encodeHeader(w, SrsMp4ESTagESDescrTag, v.nbPayload()); err != nil {
+        return
+    }
+
+    data := (v.streamDependenceFlag & 0x01) << 7 | (v.URL_Flag & 0x01) << 6 | (v.OCRstreamFlag & 0x01) << 5 | v.streamPriority & 0x1f
+    if err = binary.Write(w, binary.BigEndian, v.ES_ID); err == nil {
+        err = binary.Write(w, binary.BigEndian, data)
+    }
+    if err == nil && v.streamDependenceFlag == 0x01 {
+        err = binary.Write(w, binary.BigEndian, v.dependsOn_ES_ID)
+    }
+    if err == nil && v.URL_Flag == 0x01 {
+        err = binary.Write(w, binary.BigEndian, append([]uint8{uint8(len(v.URLstring))}, v.URLstring...))
+    }
+    if err == nil && v.OCRstreamFlag == 0x01 {
+        err = binary.Write(w, binary.BigEndian, v.OCR_ES_Id)
+    }
+    if err != nil {
+        ol.E(nil, fmt.Sprintf("write ES_Descriptor failed, err is %v", err))
+        return
+    }
+
+    if err = v.decConfigDescr.encode(w); err != nil {
+        return
+    }
+    if err = v.slConfigDescr.encode(w); err != nil {
+        return
+    }
+
+    if _, err = w.Write(v.extra); err != nil {
+        ol.E(nil, fmt.Sprintf("write ES_Descriptor extra failed, err is %v", err))
+        return
+    }
+    return
+}
+
+/**
+ * 5.6 Sample Description Boxes
+ * Elementary Stream Descriptors (esds)
+ * ISO_IEC_14496-14-MP4-2003.pdf, page 15
+ * @see http://www.mp4ra.org/codecs.html
+ */
+type Mp4EsdsBox struct {
+    Mp4FullBox
+    es *Mp4ES_Descriptor
+}
+
+func  NewMp4EsdsBox() *Mp4EsdsBox {
+    v := &Mp4EsdsBox{
+        es: NewMp4ES_Descriptor(),
+    }
+    return v
+}
+
+func (v *Mp4EsdsBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4EsdsBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    left := int32(math.MaxInt32)
+    if v.left() < uint64(left) {
+        left = int32(v.left())
+    }
+    if err = v.es.decode(r, left); err != nil {
+        ol.E(nil, fmt.Sprintf("decode esds box failed, err is %v", err))
+    }
+    ol.T(nil, fmt.Sprintf("before decode esds content, used=%v es_len=%v", v.UsedSize, v.es.total))
+
+    v.UsedSize += uint64(v.es.total)
+    return
+}
+
+func (v *Mp4EsdsBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + int(v.es.nbBytes(v.es.nbPayload()))
+}
+
+func (v *Mp4EsdsBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.es.encode(w); err != nil {
+        ol.E(nil, fmt.Sprintf("encode esds box failed, err is %v", err))
+        return
+    }
+    return
+}
+
+func (v *Mp4EsdsBox) Asc() (*Mp4DecoderSpecificInfo, error) {
+    return v.es.decConfigDescr.descSpecificInfo, nil
+}
+
+// Get the objectTypeIndication of DecoderConfigDescriptor, for example, SrsMp4ObjectTypeAac.
+func (v *Mp4EsdsBox) ObjectTypeIndication() uint8 {
+    return v.es.decConfigDescr.objectTypeIndication
+}
+
+/**
+ * 8.5.2 Sample Description Box (stsd), for Audio/Video.
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 40
+ * The sample description table gives detailed information about the coding type used, and any initialization
+ * information needed for that coding.
+ */
+type Mp4SampleDescritionBox struct {
+    Mp4FullBox
+    Entries []Box
+}
+
+func NewMp4SampleDescritionBox() *Mp4SampleDescritionBox {
+    v := &Mp4SampleDescritionBox{
+        Entries: []Box{},
+    }
+    return v
+}
+
+func (v *Mp4SampleDescritionBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+func (v *Mp4SampleDescritionBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    var nbEntries uint32
+    if err = v.Read(r, &nbEntries); err != nil {
+        ol.E(nil, fmt.Sprintf("read stsd number entries failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < int(nbEntries); i++ {
+        mb := NewMp4Box()
+        var subBox Box
+        if subBox, err = mb.discovery(r); err != nil {
+            return
+        }
+        subBox.Basic().StartPos = v.StartPos + int(v.UsedSize)
+
+        // The QuickTime sound description of version 1 and 2 has extra fields.
+        if entry, ok := subBox.(*Mp4AudioSampleEntry); ok {
+            entry.quickTime = v.Version == 0
+        }
+
+        if err = subBox.DecodeHeader(r); err != nil {
+            return
+        }
+
+        if err = subBox.Basic().DecodeBoxes(r); err != nil {
+            return
+        }
+
+        v.Entries = append(v.Entries, subBox)
+        v.UsedSize += subBox.Basic().Size()
+
+        ol.T(nil, fmt.Sprintf("decode one entry, box:%v, basic.sz=%v, usedSize=%v, left=%v", reflect.TypeOf(subBox), subBox.Basic().Size(), v.UsedSize, v.left()))
+    }
+
+    ol.T(nil, fmt.Sprintf("decode stsd box success, box:%+v", v))
+    return
+}
+
+// The size of header and entry_count, the entries are encoded as the contained boxes, see Contained.
+func (v *Mp4SampleDescritionBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 4
+}
+
+func (v *Mp4SampleDescritionBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+    if err = v.Write(w, uint32(len(v.Entries))); err != nil {
+        ol.E(nil, fmt.Sprintf("write stsd number entries failed, err is %v", err))
+        return
+    }
+    return
+}
+
+func (v *Mp4SampleDescritionBox) Mp4a() (*Mp4AudioSampleEntry, error) {
+    for _, entry := range v.Entries {
+        if et, ok := entry.(*Mp4AudioSampleEntry); ok && et.BoxType == SrsMp4BoxTypeMP4A {
+            return et, nil
+        }
+    }
+    return nil, fmt.Errorf("can't find mp4a in stsd")
+}
+
+func (v *Mp4SampleDescritionBox) Opus() (*Mp4AudioSampleEntry, error) {
+    for _, entry := range v.Entries {
+        if et, ok := entry.(*Mp4AudioSampleEntry); ok && et.BoxType == SrsMp4BoxTypeOPUS {
+            return et, nil
+        }
+    }
+    return nil, fmt.Errorf("can't find Opus in stsd")
+}
+
+// Get the audio sample entry, whatever the codec, for example, mp4a or Opus.
+func (v *Mp4SampleDescritionBox) Audio() (*Mp4AudioSampleEntry, error) {
+    for _, entry := range v.Entries {
+        if et, ok := entry.(*Mp4AudioSampleEntry); ok {
+            return et, nil
+        }
+    }
+    return nil, fmt.Errorf("can't find audio sample entry in stsd")
+}
+
+func (v *Mp4SampleDescritionBox) Avc1() (*Mp4VisualSampleEntry, error) {
+    for _, entry := range v.Entries {
+        if et, ok := entry.(*Mp4VisualSampleEntry); ok && et.BoxType == SrsMp4BoxTypeAVC1 {
+            return et, nil
+        }
+    }
+    return nil, fmt.Errorf("can't find avc1 in stsd")
+}
+
+func (v *Mp4SampleDescritionBox) Hvc1() (*Mp4VisualSampleEntry, error) {
+    for _, entry := range v.Entries {
+        if et, ok := entry.(*Mp4VisualSampleEntry); ok && (et.BoxType == SrsMp4BoxTypeHVC1 || et.BoxType == SrsMp4BoxTypeHEV1) {
+            return et, nil
+        }
+    }
+    return nil, fmt.Errorf("can't find hvc1 or hev1 in stsd")
+}
+
+func (v *Mp4SampleDescritionBox) Av01() (*Mp4VisualSampleEntry, error) {
+    for _, entry := range v.Entries {
+        if et, ok := entry.(*Mp4VisualSampleEntry); ok && et.BoxType == SrsMp4BoxTypeAV01 {
+            return et, nil
+        }
+    }
+    return nil, fmt.Errorf("can't find av01 in stsd")
+}
+
+func (v *Mp4SampleDescritionBox) Vp09() (*Mp4VisualSampleEntry, error) {
+    for _, entry := range v.Entries {
+        if et, ok := entry.(*Mp4VisualSampleEntry); ok && (et.BoxType == SrsMp4BoxTypeVP09 || et.BoxType == SrsMp4BoxTypeVP08) {
+            return et, nil
+        }
+    }
+    return nil, fmt.Errorf("can't find vp09 or vp08 in stsd")
+}
+
+// Get the visual sample entry, whatever the codec, for example, avc1 or hvc1.
+func (v *Mp4SampleDescritionBox) Visual() (*Mp4VisualSampleEntry, error) {
+    for _, entry := range v.Entries {
+        if et, ok := entry.(*Mp4VisualSampleEntry); ok {
+            return et, nil
+        }
+    }
+    return nil, fmt.Errorf("can't find visual sample entry in stsd")
+}
+
+/**
+ * 8.6.1.2 Decoding Time to Sample Box (stts), for Audio/Video.
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 48
+ */
+type Mp4SttsEntry struct {
+    // an integer that counts the number of consecutive samples that have the given
+    // duration.
+    SampleCount uint32 `json:"sample_count"`
+    // an integer that gives the delta of these samples in the time-scale of the media.
+    SampleDelta uint32 `json:"sample_delta"`
+}
+
+/**
+ * 8.6.1.2 Decoding Time to Sample Box (stts), for Audio/Video.
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 48
+ * This box contains a compact version of a table that allows indexing from decoding time to sample number.
+ * Other tables give sample sizes and pointers, from the sample number. Each entry in the table gives the
+ * number of consecutive samples with the same time delta, and the delta of those samples. By adding the
+ * deltas a complete time-to-sample map may be built.
+ */
+type Mp4DecodingTime2SampleBox struct {
+    Mp4FullBox
+    // an integer that gives the number of entries in the following table.
+    EntryCount uint32
+    Entries []*Mp4SttsEntry
+}
+
+func NewMp4DecodingTime2SampleBox() *Mp4DecodingTime2SampleBox {
+    v := &Mp4DecodingTime2SampleBox{
+        Entries: []*Mp4SttsEntry{},
+    }
+    return v
+}
+
+func (v *Mp4DecodingTime2SampleBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read stts entry count failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < int(v.EntryCount); i++ {
+        entry := &Mp4SttsEntry{}
+        if err = v.Read(r, &entry.SampleCount); err != nil {
+            ol.E(nil, fmt.Sprintf("read stts entry sample count failed, err is %v", err))
+            return
+        }
+        if err = v.Read(r, &entry.SampleDelta); err != nil {
+            ol.E(nil, fmt.Sprintf("read stts entry sample delta failed, err is %v", err))
+            return
+        }
+        ol.T(nil, fmt.Sprintf("decode one stts entry, entry=%+v", entry))
+        v.Entries = append(v.Entries, entry)
+    }
+
+    ol.T(nil, fmt.Sprintf("decode stts box success, box=%+v", v))
+    return
+}
+
+func (v *Mp4DecodingTime2SampleBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 4 + 8 * len(v.Entries)
+}
+
+func (v *Mp4DecodingTime2SampleBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    v.EntryCount = uint32(len(v.Entries))
+    if err = v.Write(w, v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("write stts entry count failed, err is %v", err))
+        return
+    }
+
+    for i, entry := range v.Entries {
+        if err = v.Write(w, entry.SampleCount, entry.SampleDelta); err != nil {
+            ol.E(nil, fmt.Sprintf("write stts %v entry failed, err is %v", i, err))
+            return
+        }
+    }
+    return
+}
+
+func (v *Mp4DecodingTime2SampleBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+/**
+ * 8.6.1.3 Composition Time to Sample Box (ctts), for Video.
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 49
+ */
+type Mp4CttsEntry struct {
+    // an integer that counts the number of consecutive samples that have the given offset.
+    SampleCount uint32 `json:"sample_count"`
+    // uint32_t for version=0
+    // int32_t for version=1
+    // an integer that gives the offset between CT and DT, such that CT(n) = DT(n) +
+    // CTTS(n).
+    SampleOffset int64 `json:"sample_offset"`
+}
+
+/**
+* 8.6.1.3 Composition Time to Sample Box (ctts), for Video.
+* ISO_IEC_14496-12-base-format-2012.pdf, page 49
+* This box provides the offset between decoding time and composition time. In version 0 of this box the
+* decoding time must be less than the composition time, and the offsets are expressed as unsigned numbers
+* such that CT(n) = DT(n) + CTTS(n) where CTTS(n) is the (uncompressed) table entry for sample n. In version
+* 1 of this box, the composition timeline and the decoding timeline are still derived from each other, but the
+* offsets are signed. It is recommended that for the computed composition timestamps, there is exactly one with
+* the value 0 (zero).
+*/
+type Mp4CompositionTime2SampleBox struct {
+    Mp4FullBox
+    EntryCount uint32
+    Entries []*Mp4CttsEntry
+}
+
+func NewMp4CompositionTime2SampleBox() *Mp4CompositionTime2SampleBox {
+    v := &Mp4CompositionTime2SampleBox{
+        Entries: []*Mp4CttsEntry{},
+    }
+    return v
+}
+
+func (v *Mp4CompositionTime2SampleBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read stts entry count failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < int(v.EntryCount); i++ {
+        entry := &Mp4CttsEntry{}
+        if err = v.Read(r, &entry.SampleCount); err != nil {
+            ol.E(nil, fmt.Sprintf("read ctts entry sample count failed, err is %v", err))
+            return
+        }
+        if v.Version == 0 {
+            var offset uint32
+            v.Read(r, &offset)
+            entry.SampleOffset = int64(offset)
+        } else if v.Version == 1 {
+            var offset int32
+            v.Read(r, &offset)
+            entry.SampleOffset = int64(offset)
+        }
+        ol.T(nil, fmt.Sprintf("decode one ctts entry, entry=%+v", entry))
+        v.Entries = append(v.Entries, entry)
+    }
+
+    ol.T(nil, fmt.Sprintf("decode ctts box success, box=%+v", v))
+    return
+}
+
+func (v *Mp4CompositionTime2SampleBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 4 + 8 * len(v.Entries)
+}
+
+func (v *Mp4CompositionTime2SampleBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    v.EntryCount = uint32(len(v.Entries))
+    if err = v.Write(w, v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("write ctts entry count failed, err is %v", err))
+        return
+    }
+
+    for i, entry := range v.Entries {
+        if v.Version == 0 {
+            err = v.Write(w, entry.SampleCount, uint32(entry.SampleOffset))
+        } else {
+            err = v.Write(w, entry.SampleCount, int32(entry.SampleOffset))
+        }
+        if err != nil {
+            ol.E(nil, fmt.Sprintf("write ctts %v entry failed, err is %v", i, err))
+            return
+        }
+    }
+    return
+}
+
+func (v *Mp4CompositionTime2SampleBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+/**
+ * 8.6.2 Sync Sample Box (stss), for Video.
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 51
+ * This box provides a compact marking of the sync samples within the stream. The table is arranged in strictly
+ * increasing order of sample number.
+ */
+type Mp4SyncSampleBox struct {
+    Mp4FullBox
+    // an integer that gives the number of entries in the following table. If entry_count is zero,
+    // there are no sync samples within the stream and the following table is empty.
+    EntryCount uint32
+    // the numbers of the samples that are sync samples in the stream.
+    SampleNumbers []uint32
+}
+
+func NewMp4SyncSampleBox() *Mp4SyncSampleBox {
+    v := &Mp4SyncSampleBox{
+        SampleNumbers: []uint32{},
+    }
+    return v
+}
+
+func (v *Mp4SyncSampleBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read stss entry count failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < int(v.EntryCount); i++ {
+        var sm uint32
+        if err = v.Read(r, &sm); err != nil {
+            ol.T(nil, fmt.Sprintf("read stss entry %v sample number failed, err is %v", i, err))
+            return
+        }
+        v.SampleNumbers = append(v.SampleNumbers, sm)
+    }
+
+    ol.T(nil, fmt.Sprintf("decode stss box success, box=%+v", v))
+    return
+}
+
+func (v *Mp4SyncSampleBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 4 + 4 * len(v.SampleNumbers)
+}
+
+func (v *Mp4SyncSampleBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    v.EntryCount = uint32(len(v.SampleNumbers))
+    if err = v.Write(w, v.EntryCount, v.SampleNumbers); err != nil {
+        ol.E(nil, fmt.Sprintf("write stss failed, err is %v", err))
+        return
+    }
+    return
+}
+
+func (v *Mp4SyncSampleBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+/**
+ * 8.7.4 Sample To Chunk Box (stsc), for Audio/Video.
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 58
+ */
+type Mp4StscEntry struct {
+    FirstChunk uint32 `json:"first_chunk"`
+    SamplesPerChunk uint32 `json:"samples_per_chunk"`
+    SampleDescriptionIndex uint32 `json:"sample_description_index"`
+}
+
+/**
+ * 8.7.4 Sample To Chunk Box (stsc), for Audio/Video.
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 58
+ * Samples within the media data are grouped into chunks. Chunks can be of different sizes, and the samples
+ * within a chunk can have different sizes. This table can be used to find the chunk that contains a sample,
+ * its position, and the associated sample description.
+ */
+type Mp4Sample2ChunkBox struct {
+    Mp4FullBox
+    // an integer that gives the number of entries in the following table
+    EntryCount uint32
+    // the numbers of the samples that are sync samples in the stream.
+    Entries []*Mp4StscEntry
+}
+
+func NewMp4Sample2ChunkBox() *Mp4Sample2ChunkBox {
+    v := &Mp4Sample2ChunkBox{
+        Entries: []*Mp4StscEntry{},
+    }
+    return v
+}
+
+func (v *Mp4Sample2ChunkBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read stsc entry count failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < int(v.EntryCount); i++ {
+        entry := &Mp4StscEntry{}
+        if err = v.Read(r, &entry.FirstChunk); err != nil {
+            ol.E(nil, fmt.Sprintf("read stsc %v entry first chunk failed, err is %v", i ,err))
+            return
+        }
+        if err = v.Read(r, &entry.SamplesPerChunk); err != nil {
+            ol.E(nil, fmt.Sprintf("read stsc %v entry samples per chunk failed, err is %v", i ,err))
+            return
+        }
+        if err = v.Read(r, &entry.SampleDescriptionIndex); err != nil {
+            ol.E(nil, fmt.Sprintf("read stsc %v entry samples description index failed, err is %v", i ,err))
+            return
+        }
+        ol.T(nil, fmt.Sprintf("decode stsc entry ok, entry=%+v", entry))
+        v.Entries = append(v.Entries, entry)
+    }
+
+    ol.T(nil, fmt.Sprintf("decode stsc box success, box=%+v", v))
+    return
+}
+
+func (v *Mp4Sample2ChunkBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 4 + 12 * len(v.Entries)
+}
+
+func (v *Mp4Sample2ChunkBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    v.EntryCount = uint32(len(v.Entries))
+    if err = v.Write(w, v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("write stsc entry count failed, err is %v", err))
+        return
+    }
+
+    for i, entry := range v.Entries {
+        if err = v.Write(w, entry.FirstChunk, entry.SamplesPerChunk, entry.SampleDescriptionIndex); err != nil {
+            ol.E(nil, fmt.Sprintf("write stsc %v entry failed, err is %v", i, err))
+            return
+        }
+    }
+    return
+}
+
+func (v *Mp4Sample2ChunkBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+/**
+ * 8.7.3.2 Sample Size Box (stsz), for Audio/Video.
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 58
+ * This box contains the sample count and a table giving the size in bytes of each sample. This allows the media data
+ * itself to be unframed. The total number of samples in the media is always indicated in the sample count.
+ */
+type Mp4SampleSizeBox struct {
+    Mp4FullBox
+    // the default sample size. If all the samples are the same size, this field
+    // contains that size value. If this field is set to 0, then the samples have different sizes, and those sizes
+    // are stored in the sample size table. If this field is not 0, it specifies the constant sample size, and no
+    // array follows.
+    SampleSize uint32
+    // an integer that gives the number of samples in the track; if sample-size is 0, then it is
+    // also the number of entries in the following table.
+    SampleCount uint32
+    // each entry_size is an integer specifying the size of a sample, indexed by its number.
+    EntrySizes []uint32
+}
+
+func NewMp4SampleSizeBox() *Mp4SampleSizeBox {
+    v := &Mp4SampleSizeBox{
+        EntrySizes: []uint32{},
+    }
+    return v
+}
+
+func (v *Mp4SampleSizeBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.SampleSize); err != nil {
+        ol.E(nil ,fmt.Sprintf("read stsz sample size failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.SampleCount); err!= nil {
+        ol.E(nil, fmt.Sprintf("read stsz sample count failed, err is %v", err))
+        return
+    }
+
+    if v.SampleSize == 0 {
+        for i := 0; i < int(v.SampleCount); i++ {
+            var size uint32
+            if err = v.Read(r, &size); err != nil {
+                ol.E(nil, fmt.Sprintf("read stsz %v entry size failed, err is %v", i, err))
+                return
+            }
+            v.EntrySizes = append(v.EntrySizes, size)
+        }
+    }
+
+    ol.T(nil, fmt.Sprintf("decode stsz box success, box=%+v", v))
+    return
+}
+
+func (v *Mp4SampleSizeBox) NbHeader() int {
+    size := v.Mp4FullBox.NbHeader() + 4 + 4
+    if v.SampleSize == 0 {
+        size += 4 * len(v.EntrySizes)
+    }
+    return size
+}
+
+func (v *Mp4SampleSizeBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    if v.SampleSize == 0 {
+        v.SampleCount = uint32(len(v.EntrySizes))
+    }
+    if err = v.Write(w, v.SampleSize, v.SampleCount); err != nil {
+        ol.E(nil, fmt.Sprintf("write stsz sample size and count failed, err is %v", err))
+        return
+    }
+
+    if v.SampleSize == 0 {
+        if err = v.Write(w, v.EntrySizes); err != nil {
+            ol.E(nil, fmt.Sprintf("write stsz entry sizes failed, err is %v", err))
+            return
+        }
+    }
+    return
+}
+
+// Get the size of sample, starts from 0, for the constant or variable sizes.
+func (v *Mp4SampleSizeBox) EntrySize(index int) uint32 {
+    if v.SampleSize != 0 {
+        return v.SampleSize
+    }
+    if index < 0 || index >= len(v.EntrySizes) {
+        return 0
+    }
+    return v.EntrySizes[index]
+}
+
+func (v *Mp4SampleSizeBox) NbSamples() int {
+    return int(v.SampleCount)
+}
+
+func (v *Mp4SampleSizeBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+/**
+ * 8.7.3.3 Compact Sample Size Box (stz2), for Audio/Video.
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 59
+ * This box contains the sample count and a table giving the size in bytes of each sample, in 4, 8 or 16 bits,
+ * which saves space when the sizes are small, for example, the audio.
+ */
+type Mp4CompactSampleSizeBox struct {
+    Mp4FullBox
+    Reserved [3]uint8
+    // an integer specifying the size in bits of the entries in the following table; it shall
+    // take the value 4, 8 or 16. If the value 4 is used, then each byte contains two values: entry[i]<<4 +
+    // entry[i+1]; if the sizes do not fill an integral number of bytes, the last byte is padded with zeros.
+    FieldSize uint8
+    // an integer that gives the number of entries in the following table
+    SampleCount uint32
+    // an integer specifying the size of a sample, indexed by its number.
+    EntrySizes []uint16
+}
+
+func NewMp4CompactSampleSizeBox() *Mp4CompactSampleSizeBox {
+    v := &Mp4CompactSampleSizeBox{
+        FieldSize: 16,
+        EntrySizes: []uint16{},
+    }
+    v.BoxType = SrsMp4BoxTypeSTZ2
+    return v
+}
+
+func (v *Mp4CompactSampleSizeBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, v.Reserved[:]); err != nil {
+        ol.E(nil, fmt.Sprintf("read stz2 reserved failed, err is %v", err))
+        return
+    }
+
+    if err = v.Read(r, &v.FieldSize); err != nil {
+        ol.E(nil, fmt.Sprintf("read stz2 field size failed, err is %v", err))
+        return
+    }
+    if v.FieldSize != 4 && v.FieldSize != 8 && v.FieldSize != 16 {
+        err = fmt.Errorf("invalid stz2 field size %v", v.FieldSize)
+        ol.E(nil, err.Error())
+        return
+    }
+
+    if err = v.Read(r, &v.SampleCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read stz2 sample count failed, err is %v", err))
+        return
+    }
+
+    // The table, the last byte is padded for the odd number of 4 bits entries.
+    nbTable := (uint64(v.SampleCount) * uint64(v.FieldSize) + 7) / 8
+    if nbTable > v.left() {
+        err = fmt.Errorf("stz2 table %v overflow the box, left=%v", nbTable, v.left())
+        ol.E(nil, err.Error())
+        return
+    }
+    data := make([]uint8, nbTable)
+    if err = v.Read(r, data); err != nil {
+        ol.E(nil, fmt.Sprintf("read stz2 table failed, err is %v", err))
+        return
+    }
+
+    v.EntrySizes = make([]uint16, v.SampleCount)
+    for i := range v.EntrySizes {
+        switch v.FieldSize {
+        case 4:
+            if b := data[i / 2]; i % 2 == 0 {
+                v.EntrySizes[i] = uint16(b >> 4)
+            } else {
+                v.EntrySizes[i] = uint16(b & 0x0f)
+            }
+        case 8:
+            v.EntrySizes[i] = uint16(data[i])
+        case 16:
+            v.EntrySizes[i] = binary.BigEndian.Uint16(data[i * 2:])
+        }
+    }
+
+    ol.T(nil, fmt.Sprintf("decode stz2 box success, field size=%v, sample count=%v", v.FieldSize, v.SampleCount))
+    return
+}
+
+func (v *Mp4CompactSampleSizeBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 3 + 1 + 4 + (len(v.EntrySizes) * int(v.FieldSize) + 7) / 8
+}
+
+func (v *Mp4CompactSampleSizeBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    v.SampleCount = uint32(len(v.EntrySizes))
+    if err = v.Write(w, v.Reserved, v.FieldSize, v.SampleCount); err != nil {
+        ol.E(nil, fmt.Sprintf("write stz2 failed, err is %v", err))
+        return
+    }
+
+    data := make([]uint8, (len(v.EntrySizes) * int(v.FieldSize) + 7) / 8)
+    for i, size := range v.EntrySizes {
+        switch v.FieldSize {
+        case 4:
+            if i % 2 == 0 {
+                data[i / 2] |= uint8(size & 0x0f) << 4
+            } else {
+                data[i / 2] |= uint8(size & 0x0f)
+            }
+        case 8:
+            data[i] = uint8(size)
+        case 16:
+            binary.BigEndian.PutUint16(data[i * 2:], size)
+        }
+    }
+    if err = v.Write(w, data); err != nil {
+        ol.E(nil, fmt.Sprintf("write stz2 table failed, err is %v", err))
+        return
+    }
+    return
+}
+
+func (v *Mp4CompactSampleSizeBox) NbSamples() int {
+    return int(v.SampleCount)
+}
+
+// Get the size of sample, starts from 0.
+func (v *Mp4CompactSampleSizeBox) EntrySize(index int) uint32 {
+    if index < 0 || index >= len(v.EntrySizes) {
+        return 0
+    }
+    return uint32(v.EntrySizes[index])
+}
+
+func (v *Mp4CompactSampleSizeBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+/**
+ * 8.7.5 Chunk Offset Box (stco), for Audio/Video.
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 59
+ * The chunk offset table gives the index of each chunk into the containing file. There are two variants, permitting
+ * the use of 32-bit or 64-bit offsets. The latter is useful when managing very large presentations. At most one of
+ * these variants will occur in any single instance of a sample table.
+ */
+type Mp4ChunkOffsetBox struct {
+    Mp4FullBox
+    // an integer that gives the number of entries in the following table
+    EntryCount uint32
+    // a 32 bit integer that gives the offset of the start of a chunk into its containing
+    // media file.
+    Entries []uint32
+}
+
+func NewMp4ChunkOffsetBox() *Mp4ChunkOffsetBox {
+    v := &Mp4ChunkOffsetBox{
+        Entries: []uint32{},
+    }
+    return v
+}
+
+func (v *Mp4ChunkOffsetBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read stco entry count failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < int(v.EntryCount); i++ {
+        var entry uint32
+        if err = v.Read(r, &entry); err != nil {
+            ol.E(nil, fmt.Sprintf("read stco %v entry failed, err is %v", i, err))
+            return
+        }
+        v.Entries = append(v.Entries, entry)
+    }
+
+    ol.T(nil, fmt.Sprintf("decode stco box success, box=%+v", v))
+    return
+}
+
+func (v *Mp4ChunkOffsetBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 4 + 4 * len(v.Entries)
+}
+
+func (v *Mp4ChunkOffsetBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    v.EntryCount = uint32(len(v.Entries))
+    if err = v.Write(w, v.EntryCount, v.Entries); err != nil {
+        ol.E(nil, fmt.Sprintf("write stco failed, err is %v", err))
+        return
+    }
+    return
+}
+
+func (v *Mp4ChunkOffsetBox) NbChunks() int {
+    return len(v.Entries)
+}
+
+func (v *Mp4ChunkOffsetBox) ChunkOffset(index int) uint64 {
+    return uint64(v.Entries[index])
+}
+
+func (v *Mp4ChunkOffsetBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+/**
+ * 8.7.5 Chunk Large Offset Box (co64), for Audio/Video.
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 59
+ * The chunk offset table gives the index of each chunk into the containing file. There are two variants, permitting
+ * the use of 32-bit or 64-bit offsets. The latter is useful when managing very large presentations. At most one of
+ * these variants will occur in any single instance of a sample table.
+ */
+type Mp4ChunkLargeOffsetBox struct {
+    Mp4FullBox
+    // an integer that gives the number of entries in the following table
+    EntryCount uint32
+    // a 64 bit integer that gives the offset of the start of a chunk into its containing
+    // media file.
+    Entries []uint64
+}
+
+func NewMp4ChunkLargeOffsetBox() *Mp4ChunkLargeOffsetBox {
+    v := &Mp4ChunkLargeOffsetBox{
+        Entries: []uint64{},
+    }
+    v.BoxType = SrsMp4BoxTypeCO64
+    return v
+}
+
+func (v *Mp4ChunkLargeOffsetBox) DecodeHeader(r io.Reader) (err error) {
+    if err = v.Mp4FullBox.DecodeHeader(r); err != nil {
+        return
+    }
+
+    if err = v.Read(r, &v.EntryCount); err != nil {
+        ol.E(nil, fmt.Sprintf("read co64 entry count failed, err is %v", err))
+        return
+    }
+
+    for i := 0; i < int(v.EntryCount); i++ {
+        var entry uint64
+        if err = v.Read(r, &entry); err != nil {
+            ol.E(nil, fmt.Sprintf("read co64 %v entry failed, err is %v", i, err))
+            return
+        }
+        v.Entries = append(v.Entries, entry)
+    }
+
+    ol.T(nil, fmt.Sprintf("decode co64 box success, box=%+v", v))
+    return
+}
+
+func (v *Mp4ChunkLargeOffsetBox) NbHeader() int {
+    return v.Mp4FullBox.NbHeader() + 4 + 8 * len(v.Entries)
+}
+
+func (v *Mp4ChunkLargeOffsetBox) EncodeHeader(w io.Writer) (err error) {
+    if err = v.Mp4FullBox.EncodeHeader(w); err != nil {
+        return
+    }
+
+    v.EntryCount = uint32(len(v.Entries))
+    if err = v.Write(w, v.EntryCount, v.Entries); err != nil {
+        ol.E(nil, fmt.Sprintf("write co64 failed, err is %v", err))
+        return
+    }
+    return
+}
+
+func (v *Mp4ChunkLargeOffsetBox) NbChunks() int {
+    return len(v.Entries)
+}
+
+func (v *Mp4ChunkLargeOffsetBox) ChunkOffset(index int) uint64 {
+    return v.Entries[index]
+}
+
+func (v *Mp4ChunkLargeOffsetBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+/**
+ * 8.10.1 User Data Box (udta)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 78
+ * This box contains objects that declare user information about the containing box and its data (presentation or
+ * track).
+ */
+type Mp4UserDataBox struct {
+    Mp4Box
+    // The position and size of the payload in file, which is skipped when decoding.
+    DataOffset int
+    NbData int
+    Data []uint8
+}
+
+func NewMp4UserDataBox() *Mp4UserDataBox {
+    v := &Mp4UserDataBox{
+        Data: []uint8{},
+    }
+    return v
+}
+
+func (v *Mp4UserDataBox) DecodeHeader(r io.Reader) (err error) {
+    v.DataOffset = v.StartPos + int(v.UsedSize)
+    v.NbData = int(v.left())
+    if err = v.Skip(r, v.left()); err != nil {
+        return
+    }
+    ol.T(nil, fmt.Sprintf("decode udta box success, nb data=%v", v.NbData))
+    return
+}
+
+func (v *Mp4UserDataBox) NbHeader() int {
+    return v.Mp4Box.NbHeader() + v.NbData
+}
+
+func (v *Mp4UserDataBox) Payload() (offset int, size int) {
+    return v.DataOffset, v.NbData
+}
+
+func (v *Mp4UserDataBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+/**
+ * 8.1.1 Media Data Box (mdat)
+ * ISO_IEC_14496-12-base-format-2012.pdf, page 29
+ * This box contains the media data. In video tracks, this box would contain video frames.
+ * A presentation may contain zero or more Media Data Boxes. The actual media data follows the type field;
+ * its structure is described by the metadata (see particularly the sample table, subclause 8.5, and the
+ * item location box, subclause 8.11.3).
+ */
+type Mp4MediaDataBox struct {
+    Mp4Box
+    // The position and size of the payload in file, which is skipped when decoding, and the samples
+    // are fetched from it later.
+    DataOffset int
+    NbData int
+    Data []uint8
+}
+
+func NewMp4MediaDataBox() *Mp4MediaDataBox {
+    v:= &Mp4MediaDataBox{
+        Data: []uint8{},
+    }
+    return v
+}
+
+func (v *Mp4MediaDataBox) DecodeHeader(r io.Reader) (err error) {
+    v.DataOffset = v.StartPos + int(v.UsedSize)
+    v.NbData = int(v.left())
+    if err = v.Skip(r, v.left()); err != nil {
+        return
+    }
+    ol.T(nil, fmt.Sprintf("decode mdat box success, data offset=%v, nb data=%v", v.DataOffset, v.NbData))
+    return
+}
+
+func (v *Mp4MediaDataBox) NbHeader() int {
+    return v.Mp4Box.NbHeader() + v.NbData
+}
+
+func (v *Mp4MediaDataBox) Payload() (offset int, size int) {
+    return v.DataOffset, v.NbData
+}
+
+func (v *Mp4MediaDataBox) Basic() *Mp4Box {
+    return &v.Mp4Box
+}
+
+
+
+
+
+
+
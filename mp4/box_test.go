@@ -0,0 +1,604 @@
+package mp4
+
+import (
+    "bytes"
+    "encoding/binary"
+    "strings"
+    "testing"
+)
+
+// The plain reader which records the bytes read, the Seek of the underlayer is hidden.
+type countingReader struct {
+    r *bytes.Reader
+    nbRead int
+    maxRead int
+}
+
+func (v *countingReader) Read(p []byte) (n int, err error) {
+    n, err = v.r.Read(p)
+    v.nbRead += n
+    if len(p) > v.maxRead {
+        v.maxRead = len(p)
+    }
+    return
+}
+
+// The reader which records the bytes read, and seeks.
+type countingReadSeeker struct {
+    *countingReader
+}
+
+func (v countingReadSeeker) Seek(offset int64, whence int) (int64, error) {
+    return v.r.Seek(offset, whence)
+}
+
+// The file with a large mdat, and an unknown box, whose payloads are skipped.
+func buildSkipFile() (b []byte, payload int) {
+    payload = 4 * 1024 * 1024
+    b = append(buildAvFile(false), box("skip", make([]byte, 1024))...)
+    b = append(b, box("mdat", make([]byte, payload))...)
+    return
+}
+
+func TestSkipBySeek(t *testing.T) {
+    b, payload := buildSkipFile()
+    r := &countingReader{r: bytes.NewReader(b)}
+    f, err := Parse(countingReadSeeker{r})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if r.nbRead > len(b) - payload {
+        t.Errorf("read %v bytes of file %v, the payload %v is not skipped", r.nbRead, len(b), payload)
+    }
+    checkSkipped(t, f, b, payload)
+}
+
+func TestSkipByDiscard(t *testing.T) {
+    b, payload := buildSkipFile()
+    r := &countingReader{r: bytes.NewReader(b)}
+    f, err := Parse(r)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    // The payload is discarded by a bounded buffer, never read into memory at once.
+    if r.maxRead > 64 * 1024 {
+        t.Errorf("read %v bytes at once", r.maxRead)
+    }
+    checkSkipped(t, f, b, payload)
+}
+
+func checkSkipped(t *testing.T, f *File, b []byte, payload int) {
+    t.Helper()
+    if len(f.Boxes) != 5 {
+        t.Fatalf("boxes %v", len(f.Boxes))
+    }
+
+    skip, ok := f.Boxes[3].(*Mp4FreeSpaceBox)
+    if !ok || FourCC(skip.BoxType) != "skip" || skip.Size() != 1032 {
+        t.Errorf("unknown box %+v", f.Boxes[3])
+    }
+    if offset, size := skip.Payload(); offset != skip.StartPos + 8 || size != 1024 {
+        t.Errorf("unknown box payload at %v, size %v", offset, size)
+    }
+
+    mdat, ok := f.Boxes[4].(*Mp4MediaDataBox)
+    if !ok || mdat.DataOffset != len(b) - payload || mdat.NbData != payload {
+        t.Errorf("mdat %+v", f.Boxes[4])
+    }
+
+    // The skipped payloads are copied from the source when encoding.
+    var w bytes.Buffer
+    if err := f.Encode(&w, bytes.NewReader(b)); err != nil || !bytes.Equal(w.Bytes(), b) {
+        t.Errorf("encode %v bytes, expect %v, err is %v", w.Len(), len(b), err)
+    }
+}
+
+func TestDataReference(t *testing.T) {
+    self := fullBox("url ", 0, SRS_MP4_DATA_ENTRY_SELF_CONTAINED)
+    cases := []struct {
+        name string
+        entries [][]byte
+        // The data reference index of the sample entry.
+        index uint16
+        locations []string
+    }{
+        {"self-contained", [][]byte{self}, 1, nil},
+        {"url", [][]byte{fullBox("url ", 0, 0, []byte("http://example.com/a.mp4\x00"))}, 1, []string{"http://example.com/a.mp4"}},
+        {"urn location", [][]byte{fullBox("urn ", 0, 0, []byte("urn:a\x00b.mp4\x00"))}, 1, []string{"b.mp4"}},
+        {"urn name", [][]byte{fullBox("urn ", 0, 0, []byte("urn:a\x00"))}, 1, []string{"urn:a"}},
+        {"urn self-contained", [][]byte{fullBox("urn ", 0, SRS_MP4_DATA_ENTRY_SELF_CONTAINED, []byte("urn:a\x00"))}, 1, nil},
+        {"second entry", [][]byte{self, fullBox("url ", 0, 0, []byte("b.mp4\x00"))}, 2, []string{"b.mp4"}},
+        {"first entry", [][]byte{self, fullBox("url ", 0, 0, []byte("b.mp4\x00"))}, 1, nil},
+    }
+    for _, c := range cases {
+        video, _ := newAvTracks()
+        video.dref = fullBox("dref", 0, 0, be(uint32(len(c.entries))), bytes.Join(c.entries, nil))
+        // The data reference index follows the 6 bytes reserved of sample entry.
+        video.entry = append([]byte{}, video.entry...)
+        binary.BigEndian.PutUint16(video.entry[14:], c.index)
+
+        b := buildFile(false, video)
+        f := parseFile(t, b)
+        moov, _ := f.Moov()
+        trak := moov.Tracks()[0]
+
+        dref, err := trak.Dref()
+        if err != nil || dref.EntryCount != uint32(len(c.entries)) || len(dref.Boxes) != len(c.entries) {
+            t.Errorf("%v: dref %+v, err is %v", c.name, dref, err)
+            continue
+        }
+        locations := trak.ExternalData()
+        if strings.Join(locations, ",") != strings.Join(c.locations, ",") {
+            t.Errorf("%v: external data %v, expect %v", c.name, locations, c.locations)
+        }
+        if v := NewTrackJSON(moov, trak); strings.Join(v.ExternalData, ",") != strings.Join(c.locations, ",") {
+            t.Errorf("%v: summary external data %v", c.name, v.ExternalData)
+        }
+
+        // The samples in other files are not resolved, and the chunk offsets are not patched.
+        _, err = trak.Samples()
+        if external := len(c.locations) > 0; external != (err != nil) {
+            t.Errorf("%v: samples err is %v", c.name, err)
+        }
+        if err := parseFile(t, buildFile(true, video)).Faststart(); (len(c.locations) > 0) != (err != nil) {
+            t.Errorf("%v: faststart err is %v", c.name, err)
+        }
+
+        var w bytes.Buffer
+        if err := f.Encode(&w, bytes.NewReader(b)); err != nil || !bytes.Equal(w.Bytes(), b) {
+            t.Errorf("%v: encode is not identical, err is %v", c.name, err)
+        }
+    }
+}
+
+func TestMediaHeaders(t *testing.T) {
+    cases := []struct {
+        handler string
+        mhd []byte
+        fields map[string]interface{}
+    }{
+        // The balance of full left, -1.0 in 8.8.
+        {"soun", fullBox("smhd", 0, 0, be(int16(-0x100), uint16(0))), map[string]interface{}{"balance": int16(-0x100)}},
+        {"hint", fullBox("hmhd", 0, 0, be(uint16(1400), uint16(1200), uint32(8000), uint32(6000), uint32(0))),
+            map[string]interface{}{"max_pdu_size": uint16(1400), "avg_pdu_size": uint16(1200), "max_bitrate": uint32(8000), "avg_bitrate": uint32(6000)}},
+        {"sbtl", fullBox("sthd", 0, 0), nil},
+        {"meta", fullBox("nmhd", 0, 0), nil},
+    }
+    for _, c := range cases {
+        _, audio := newAvTracks()
+        audio.handler, audio.mhd = c.handler, c.mhd
+        b := buildFile(false, audio)
+        f := parseFile(t, b)
+
+        bt := string(c.mhd[4:8])
+        mhd, err := f.Find("moov/trak/mdia/minf/" + bt)
+        if err != nil {
+            t.Errorf("%v: %v", bt, err)
+            continue
+        }
+        if _, ok := mhd.(*Mp4FreeSpaceBox); ok {
+            t.Errorf("%v: decoded as free space", bt)
+            continue
+        }
+
+        v := NewBoxJSON(mhd)
+        if v.Version == nil || *v.Version != 0 || v.Flags == nil || len(v.Fields) != len(c.fields) {
+            t.Errorf("%v: json %+v", bt, v)
+        }
+        for key, value := range c.fields {
+            if v.Fields[key] != value {
+                t.Errorf("%v: field %v is %v, expect %v", bt, key, v.Fields[key], value)
+            }
+        }
+
+        var w bytes.Buffer
+        if err := Encode(&w, mhd, nil); err != nil || !bytes.Equal(w.Bytes(), c.mhd) {
+            t.Errorf("%v: encode %x, expect %x, err is %v", bt, w.Bytes(), c.mhd, err)
+        }
+    }
+}
+
+// The hvcC of HEVC Main profile, level 3.1, with the VPS, SPS and PPS.
+var testHvcc = box("hvcC", []byte{0x01, 0x01, 0x60, 0x00, 0x00, 0x00, 0x90, 0x00, 0x00, 0x00, 0x00, 0x00, 0x5d,
+    0xf0, 0x00, 0xfc, 0xfd, 0xf8, 0xf8, 0x00, 0x00, 0x0f, 0x03},
+    []byte{0xa0, 0x00, 0x01, 0x00, 0x04, 0x40, 0x01, 0x0c, 0x01},
+    []byte{0xa1, 0x00, 0x01, 0x00, 0x03, 0x42, 0x01, 0x01},
+    []byte{0xa2, 0x00, 0x01, 0x00, 0x02, 0x44, 0x01})
+
+func TestHvcc(t *testing.T) {
+    for _, bt := range []string{"hvc1", "hev1"} {
+        video := newTestTrack(1, "vide", visualEntry(bt, 1280, 720, testHvcc), 3)
+        trak := parseTracks(t, buildFile(false, video))[0]
+
+        if trak.VideoCodec() != SrsVideoCodecIdHEVC {
+            t.Errorf("%v: codec %v", bt, trak.VideoCodec())
+        }
+        hvcc, err := trak.Hvcc()
+        if err != nil {
+            t.Fatalf("%v: %v", bt, err)
+        }
+        if hvcc.ConfigurationVersion != 1 || hvcc.GeneralProfileSpace != 0 || hvcc.GeneralTierFlag != 0 || hvcc.GeneralProfileIdc != 1 ||
+            hvcc.GeneralProfileCompatibilityFlags != 0x60000000 || hvcc.GeneralConstraintIndicatorFlags != 0x900000000000 ||
+            hvcc.GeneralLevelIdc != 93 || hvcc.ChromaFormatIdc != 1 || hvcc.BitDepthLumaMinus8 != 0 || hvcc.BitDepthChromaMinus8 != 0 ||
+            hvcc.NumTemporalLayers != 1 || hvcc.TemporalIdNested != 1 || hvcc.LengthSizeMinusOne != 3 {
+            t.Errorf("%v: hvcc %+v", bt, hvcc)
+        }
+        if len(hvcc.Arrays) != 3 || hvcc.Arrays[0].ArrayCompleteness != 1 {
+            t.Errorf("%v: arrays %+v", bt, hvcc.Arrays)
+        }
+        if vps, sps, pps := hvcc.Vps(), hvcc.Sps(), hvcc.Pps(); len(vps) != 1 || len(sps) != 1 || len(pps) != 1 ||
+            !bytes.Equal(vps[0], []byte{0x40, 0x01, 0x0c, 0x01}) || !bytes.Equal(sps[0], []byte{0x42, 0x01, 0x01}) ||
+            !bytes.Equal(pps[0], []byte{0x44, 0x01}) {
+            t.Errorf("%v: vps %x, sps %x, pps %x", bt, vps, sps, pps)
+        }
+    }
+
+    // The config is truncated in the NALU.
+    truncated := box("hvcC", testHvcc[8:len(testHvcc) - 1])
+    if _, err := Parse(bytes.NewReader(truncated)); err == nil {
+        t.Error("the truncated hvcC should fail")
+    }
+}
+
+func TestAv1c(t *testing.T) {
+    obus := []byte{0x0a, 0x0b, 0x00, 0x00, 0x00, 0x42, 0xab, 0xbf, 0xc3}
+    cases := []struct {
+        name string
+        config []byte
+        // The profile, level, tier, bit depth, monochrome, subsampling x and y, and the initial presentation delay.
+        fields [8]int
+    }{
+        {"main 8 bits", []byte{0x81, 0x08, 0x0c, 0x00}, [8]int{0, 8, 0, 8, 0, 1, 1, 0}},
+        {"main 10 bits", []byte{0x81, 0x08, 0x4c, 0x00}, [8]int{0, 8, 0, 10, 0, 1, 1, 0}},
+        {"professional 12 bits", []byte{0x81, 0x48, 0x6c, 0x00}, [8]int{2, 8, 0, 12, 0, 1, 1, 0}},
+        // The high tier, monochrome, and the initial presentation delay of 10+1 frames.
+        {"high monochrome", []byte{0x81, 0x2d, 0x9c, 0x1a}, [8]int{1, 13, 1, 8, 1, 1, 1, 11}},
+    }
+    for _, c := range cases {
+        av1c := box("av1C", c.config, obus)
+        video := newTestTrack(1, "vide", visualEntry("av01", 1920, 1080, av1c), 3)
+        trak := parseTracks(t, buildFile(false, video))[0]
+
+        if trak.VideoCodec() != SrsVideoCodecIdAV1 {
+            t.Errorf("%v: codec %v", c.name, trak.VideoCodec())
+        }
+        v, err := trak.Av1c()
+        if err != nil {
+            t.Fatalf("%v: %v", c.name, err)
+        }
+        delay := 0
+        if v.InitialPresentationDelayPresent != 0 {
+            delay = int(v.InitialPresentationDelayMinusOne) + 1
+        }
+        got := [8]int{int(v.SeqProfile), int(v.SeqLevelIdx0), int(v.SeqTier0), v.BitDepth(), int(v.Monochrome),
+            int(v.ChromaSubsamplingX), int(v.ChromaSubsamplingY), delay}
+        if v.Marker != 1 || v.Version != 1 || got != c.fields || !bytes.Equal(v.ConfigOBUs, obus) {
+            t.Errorf("%v: av1C %v, expect %v, %+v", c.name, got, c.fields, v)
+        }
+
+        var w bytes.Buffer
+        if err := Encode(&w, v, nil); err != nil || !bytes.Equal(w.Bytes(), av1c) {
+            t.Errorf("%v: encode %x, expect %x, err is %v", c.name, w.Bytes(), av1c, err)
+        }
+    }
+}
+
+func TestVpcc(t *testing.T) {
+    cases := []struct {
+        name string
+        entry string
+        vpcc []byte
+        codec int
+        // The profile, level, bit depth, chroma subsampling, full range, colour primaries, transfer and matrix.
+        fields [8]uint8
+    }{
+        // The 10 bits 4:2:0 colocated, BT.2020 and PQ.
+        {"vp09 hdr", "vp09", fullBox("vpcC", 1, 0, []byte{2, 31, 0xa2, 9, 16, 9}, be(uint16(0))),
+            SrsVideoCodecIdVP9, [8]uint8{2, 31, 10, 1, 0, 9, 16, 9}},
+        {"vp09", "vp09", fullBox("vpcC", 1, 0, []byte{0, 40, 0x82, 1, 1, 1}, be(uint16(0))),
+            SrsVideoCodecIdVP9, [8]uint8{0, 40, 8, 1, 0, 1, 1, 1}},
+        // The 4:2:0 vertical and full range.
+        {"vp08", "vp08", fullBox("vpcC", 1, 0, []byte{0, 10, 0x81, 1, 1, 1}, be(uint16(0))),
+            SrsVideoCodecIdVP8, [8]uint8{0, 10, 8, 0, 1, 1, 1, 1}},
+    }
+    for _, c := range cases {
+        video := newTestTrack(1, "vide", visualEntry(c.entry, 1920, 1080, c.vpcc), 3)
+        trak := parseTracks(t, buildFile(false, video))[0]
+
+        if trak.VideoCodec() != c.codec {
+            t.Errorf("%v: codec %v, expect %v", c.name, trak.VideoCodec(), c.codec)
+        }
+        v, err := trak.Vpcc()
+        if err != nil {
+            t.Fatalf("%v: %v", c.name, err)
+        }
+        got := [8]uint8{v.Profile, v.Level, v.BitDepth, v.ChromaSubsampling, v.VideoFullRangeFlag,
+            v.ColourPrimaries, v.TransferCharacteristics, v.MatrixCoefficients}
+        if got != c.fields || len(v.Data) != 0 {
+            t.Errorf("%v: vpcC %v, expect %v", c.name, got, c.fields)
+        }
+    }
+
+    // The vpcC of version 0 is kept as is.
+    vpcc := fullBox("vpcC", 0, 0, []byte{0, 10, 0x80, 0x06, 0x00})
+    video := newTestTrack(1, "vide", visualEntry("vp08", 640, 360, vpcc), 3)
+    v, err := parseTracks(t, buildFile(false, video))[0].Vpcc()
+    if err != nil || v.Version != 0 || !bytes.Equal(v.Data, vpcc[12:]) {
+        t.Errorf("vpcC v0 %+v, err is %v", v, err)
+    }
+}
+
+func TestDops(t *testing.T) {
+    cases := []struct {
+        name string
+        dops []byte
+        rate uint32
+        gain int16
+        family, streams, coupled uint8
+        mapping []byte
+    }{
+        // The input of 44.1 kHz, and the gain of -1 dB.
+        {"stereo", box("dOps", []byte{0, 2}, be(uint16(312), uint32(44100), int16(-256)), []byte{0}), 44100, -256, 0, 0, 0, nil},
+        // The 5.1 of 4 streams, 2 of which are coupled, in the order of Vorbis.
+        {"5.1", box("dOps", []byte{0, 6}, be(uint16(312), uint32(48000), int16(0)), []byte{1, 4, 2, 0, 4, 1, 2, 3, 5}),
+            48000, 0, 1, 4, 2, []byte{0, 4, 1, 2, 3, 5}},
+    }
+    for _, c := range cases {
+        channels := uint16(c.dops[9])
+        audio := newTestTrack(2, "soun", audioEntry("Opus", channels, 16, 48000, c.dops), 3)
+        trak := parseTracks(t, buildFile(false, audio))[0]
+
+        if trak.SoundCodec() != SrsAudioCodecIdOpus {
+            t.Errorf("%v: codec %v", c.name, trak.SoundCodec())
+        }
+        v, err := trak.Dops()
+        if err != nil {
+            t.Fatalf("%v: %v", c.name, err)
+        }
+        if v.Version != 0 || v.OutputChannelCount != uint8(channels) || v.PreSkip != 312 || v.InputSampleRate != c.rate ||
+            v.OutputGain != c.gain || v.ChannelMappingFamily != c.family ||
+            v.StreamCount != c.streams || v.CoupledCount != c.coupled || !bytes.Equal(v.ChannelMapping, c.mapping) {
+            t.Errorf("%v: dOps %+v", c.name, v)
+        }
+
+        var w bytes.Buffer
+        if err := Encode(&w, v, nil); err != nil || !bytes.Equal(w.Bytes(), c.dops) {
+            t.Errorf("%v: encode %x, expect %x, err is %v", c.name, w.Bytes(), c.dops, err)
+        }
+    }
+
+    // The channel mapping is truncated.
+    if _, err := Parse(bytes.NewReader(box("dOps", []byte{0, 6}, be(uint16(312), uint32(48000), int16(0)), []byte{1, 4, 2, 0, 4}))); err == nil {
+        t.Error("the truncated dOps should fail")
+    }
+}
+
+func TestAc3(t *testing.T) {
+    cases := []struct {
+        name string
+        entry []byte
+        codec int
+        rate uint32
+        channels int
+        bitrate uint32
+        atmos bool
+    }{
+        // The 5.1 of 448 kbps in 48 kHz, the channel count of entry is always 2.
+        {"ac-3 5.1", audioEntry("ac-3", 2, 16, 48000, box("dac3", []byte{0x10, 0x3d, 0xe0})),
+            SrsAudioCodecIdAC3, 48000, 6, 448000, false},
+        // The stereo of 192 kbps in 44.1 kHz.
+        {"ac-3 stereo", audioEntry("ac-3", 2, 16, 44100, box("dac3", []byte{0x50, 0x11, 0x40})),
+            SrsAudioCodecIdAC3, 44100, 2, 192000, false},
+        // The 5.1 of 640 kbps, no dependent substream.
+        {"ec-3 5.1", audioEntry("ec-3", 2, 16, 48000, box("dec3", []byte{0x14, 0x00, 0x20, 0x0f, 0x00})),
+            SrsAudioCodecIdEAC3, 48000, 6, 640000, false},
+        // The 7.1 of 768 kbps, the dependent substream of Lrs/Rrs, with Atmos of complexity 16.
+        {"ec-3 7.1 atmos", audioEntry("ec-3", 2, 16, 48000, box("dec3", []byte{0x18, 0x00, 0x20, 0x0f, 0x02, 0x80, 0x01, 0x10})),
+            SrsAudioCodecIdEAC3, 48000, 8, 768000, true},
+    }
+    for _, c := range cases {
+        audio := newTestTrack(2, "soun", c.entry, 3)
+        b := buildFile(false, audio)
+        moov, err := parseFile(t, b).Moov()
+        if err != nil {
+            t.Fatal(err)
+        }
+        trak := moov.Tracks()[0]
+        if trak.SoundCodec() != c.codec {
+            t.Errorf("%v: codec %v, expect %v", c.name, trak.SoundCodec(), c.codec)
+        }
+        entry, err := trak.Audio()
+        if err != nil {
+            t.Fatalf("%v: %v", c.name, err)
+        }
+
+        var rate, bitrate uint32
+        var channels int
+        var atmos bool
+        var config Box
+        if c.codec == SrsAudioCodecIdAC3 {
+            v, err := entry.Dac3()
+            if err != nil {
+                t.Fatalf("%v: %v", c.name, err)
+            }
+            rate, channels, bitrate, config = v.SampleRate(), v.ChannelCount(), v.Bitrate(), v
+        } else {
+            v, err := entry.Dec3()
+            if err != nil {
+                t.Fatalf("%v: %v", c.name, err)
+            }
+            rate, channels, bitrate, atmos, config = v.SampleRate(), v.ChannelCount(), v.Bitrate(), v.Atmos(), v
+            if len(v.Substreams) != 1 || v.Substreams[0].Bsid != 16 || v.Substreams[0].Acmod != 7 || v.Substreams[0].Lfeon != 1 {
+                t.Errorf("%v: substreams %+v", c.name, v.Substreams)
+            }
+        }
+        if rate != c.rate || channels != c.channels || bitrate != c.bitrate || atmos != c.atmos {
+            t.Errorf("%v: rate %v, channels %v, bitrate %v, atmos %v", c.name, rate, channels, bitrate, atmos)
+        }
+
+        // The summary takes the channels and rate from the config, not the entry.
+        if v := NewTrackJSON(moov, trak); int(v.ChannelCount) != c.channels || v.SampleRate != c.rate || v.Atmos != c.atmos {
+            t.Errorf("%v: summary channels %v, rate %v, atmos %v", c.name, v.ChannelCount, v.SampleRate, v.Atmos)
+        }
+
+        var w bytes.Buffer
+        if err := Encode(&w, config, nil); err != nil || !bytes.Equal(w.Bytes(), c.entry[len(c.entry) - w.Len():]) {
+            t.Errorf("%v: encode %x, err is %v", c.name, w.Bytes(), err)
+        }
+    }
+
+    // The dec3 of 3 independent substreams, but only the first is present.
+    if _, err := Parse(bytes.NewReader(box("dec3", []byte{0x14, 0x02, 0x20, 0x0f, 0x00}))); err == nil {
+        t.Error("the truncated dec3 should fail")
+    }
+}
+
+// The STREAMINFO of FLAC, with the block size 4096 and the frame size unknown.
+func flacStreamInfo(rate uint32, channels, bits uint8, total uint64) []byte {
+    return be(uint16(4096), uint16(4096), make([]byte, 6),
+        uint64(rate) << 44 | uint64(channels - 1) << 41 | uint64(bits - 1) << 36 | total, bytes.Repeat([]byte{0xee}, 16))
+}
+
+// The metadata block of FLAC, with the last flag and type in the first byte.
+func flacBlock(last bool, blockType uint8, data []byte) []byte {
+    header := uint32(blockType) << 24 | uint32(len(data))
+    if last {
+        header |= 1 << 31
+    }
+    return append(be(header), data...)
+}
+
+func TestDfla(t *testing.T) {
+    cases := []struct {
+        name string
+        rate uint32
+        channels, bits uint8
+        total uint64
+    }{
+        {"stereo", 44100, 2, 16, 441000},
+        {"5.1 hires", 96000, 6, 24, 960000},
+        // The sample rate of 20 bits overflows the 16.16 of entry.
+        {"mono 192k", 192000, 1, 32, 1 << 35},
+    }
+    for _, c := range cases {
+        // The STREAMINFO is followed by the VORBIS_COMMENT, which is the last block.
+        dfla := fullBox("dfLa", 0, 0, flacBlock(false, 0, flacStreamInfo(c.rate, c.channels, c.bits, c.total)),
+            flacBlock(true, 4, []byte("vendor")))
+        audio := newTestTrack(2, "soun", audioEntry("fLaC", 2, 16, 48000, dfla), 3)
+        moov, err := parseFile(t, buildFile(false, audio)).Moov()
+        if err != nil {
+            t.Fatal(err)
+        }
+        trak := moov.Tracks()[0]
+        if trak.SoundCodec() != SrsAudioCodecIdFLAC {
+            t.Errorf("%v: codec %v", c.name, trak.SoundCodec())
+        }
+        entry, err := trak.Audio()
+        if err != nil {
+            t.Fatalf("%v: %v", c.name, err)
+        }
+        v, err := entry.Dfla()
+        if err != nil {
+            t.Fatalf("%v: %v", c.name, err)
+        }
+
+        si := v.StreamInfo
+        if len(v.Blocks) != 2 || v.Blocks[1].BlockType != 4 || v.Blocks[1].LastMetadataBlockFlag != 1 || si == nil {
+            t.Fatalf("%v: dfLa blocks %v, streaminfo %+v", c.name, len(v.Blocks), si)
+        }
+        if si.MinBlockSize != 4096 || si.MaxBlockSize != 4096 || si.SampleRate != c.rate || si.Channels != c.channels ||
+            si.BitsPerSample != c.bits || si.TotalSamples != c.total || !bytes.Equal(si.Md5, bytes.Repeat([]byte{0xee}, 16)) {
+            t.Errorf("%v: streaminfo %+v", c.name, si)
+        }
+        if entry.SamplingRate() != c.rate || entry.Channels() != int(c.channels) || entry.BitsPerSample() != int(c.bits) {
+            t.Errorf("%v: entry rate %v, channels %v, bits %v", c.name, entry.SamplingRate(), entry.Channels(), entry.BitsPerSample())
+        }
+        if j := NewTrackJSON(moov, trak); j.SampleRate != c.rate || j.ChannelCount != uint16(c.channels) || j.BitsPerSample != uint16(c.bits) {
+            t.Errorf("%v: summary rate %v, channels %v, bits %v", c.name, j.SampleRate, j.ChannelCount, j.BitsPerSample)
+        }
+
+        var w bytes.Buffer
+        if err := Encode(&w, v, nil); err != nil || !bytes.Equal(w.Bytes(), dfla) {
+            t.Errorf("%v: encode %x, expect %x, err is %v", c.name, w.Bytes(), dfla, err)
+        }
+    }
+
+    for name, dfla := range map[string][]byte{
+        // The STREAMINFO is less than 34 bytes.
+        "short streaminfo": fullBox("dfLa", 0, 0, flacBlock(true, 0, make([]byte, 18))),
+        // The length of block overflows the box.
+        "block overflow": fullBox("dfLa", 0, 0, be(uint32(1) << 31 | 64), make([]byte, 34)),
+    } {
+        if _, err := Parse(bytes.NewReader(dfla)); err == nil {
+            t.Errorf("%v: should fail", name)
+        }
+    }
+}
+
+// The QuickTime sound description version 2 of lpcm, in 48 kHz stereo.
+func lpcmEntry(bits, flags uint32) []byte {
+    return box("lpcm", make([]byte, 6), be(uint16(1)), be(uint16(2), uint16(0), uint32(0)),
+        be(uint16(3), uint16(16), int16(-2), uint16(0), uint32(0x10000)),
+        be(uint32(72), float64(48000), uint32(2), uint32(0x7f000000), bits, flags, bits / 8 * 2, uint32(1)))
+}
+
+func TestPcm(t *testing.T) {
+    cases := []struct {
+        name string
+        entry []byte
+        rate uint32
+        bits int
+        littleEndian bool
+    }{
+        {"ipcm le", audioEntry("ipcm", 2, 24, 48000, fullBox("pcmC", 0, 0, []byte{1, 24})), 48000, 24, true},
+        {"ipcm be", audioEntry("ipcm", 2, 16, 48000, fullBox("pcmC", 0, 0, []byte{0, 16})), 48000, 16, false},
+        {"fpcm", audioEntry("fpcm", 2, 32, 48000, fullBox("pcmC", 0, 0, []byte{0, 32})), 48000, 32, false},
+        // The sowt and twos are little-endian and big-endian by the type.
+        {"sowt", audioEntry("sowt", 2, 16, 44100), 44100, 16, true},
+        {"twos", audioEntry("twos", 2, 16, 44100), 44100, 16, false},
+        // The flags of signed integer and packed, and with the big-endian.
+        {"lpcm le", lpcmEntry(24, 0x0c), 48000, 24, true},
+        {"lpcm be", lpcmEntry(24, 0x0e), 48000, 24, false},
+    }
+    for _, c := range cases {
+        audio := newTestTrack(2, "soun", c.entry, 3)
+        moov, err := parseFile(t, buildFile(false, audio)).Moov()
+        if err != nil {
+            t.Fatal(err)
+        }
+        trak := moov.Tracks()[0]
+
+        codec := SrsAudioCodecIdLinearPCMBigEndian
+        if c.littleEndian {
+            codec = SrsAudioCodecIdLinearPCMLittleEndian
+        }
+        if trak.SoundCodec() != codec {
+            t.Errorf("%v: codec %v, expect %v", c.name, trak.SoundCodec(), codec)
+        }
+        entry, err := trak.Audio()
+        if err != nil {
+            t.Fatalf("%v: %v", c.name, err)
+        }
+        if !entry.IsPCM() || entry.LittleEndian() != c.littleEndian || entry.BitsPerSample() != c.bits ||
+            entry.SamplingRate() != c.rate || entry.Channels() != 2 {
+            t.Errorf("%v: entry little-endian %v, bits %v, rate %v, channels %v", c.name, entry.LittleEndian(),
+                entry.BitsPerSample(), entry.SamplingRate(), entry.Channels())
+        }
+        if j := NewTrackJSON(moov, trak); j.BitsPerSample != uint16(c.bits) || j.Endianness != endianness(c.littleEndian) || j.SampleRate != c.rate {
+            t.Errorf("%v: summary bits %v, endianness %v, rate %v", c.name, j.BitsPerSample, j.Endianness, j.SampleRate)
+        }
+
+        var w bytes.Buffer
+        if err := Encode(&w, entry, nil); err != nil || !bytes.Equal(w.Bytes(), c.entry) {
+            t.Errorf("%v: encode %x, expect %x, err is %v", c.name, w.Bytes(), c.entry, err)
+        }
+    }
+
+    // The mp4a is not PCM.
+    audio := newTestTrack(2, "soun", audioEntry("mp4a", 2, 16, 48000, esds(0x40, []byte{0x11, 0x90})), 3)
+    if entry, err := parseTracks(t, buildFile(false, audio))[0].Audio(); err != nil || entry.IsPCM() {
+        t.Errorf("mp4a is PCM, err is %v", err)
+    }
+}
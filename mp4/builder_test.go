@@ -0,0 +1,271 @@
+package mp4
+
+import (
+    "bytes"
+    "encoding/binary"
+    "io"
+    "testing"
+    ol "github.com/ossrs/go-oryx-lib/logger"
+)
+
+func init() {
+    // The trace of decoding is verbose, discard it in tests.
+    ol.Switch(io.Discard)
+}
+
+// Write the values in big-endian, for example, be(uint32(1), []byte("moov")).
+func be(values ...interface{}) []byte {
+    var b bytes.Buffer
+    for _, v := range values {
+        if err := binary.Write(&b, binary.BigEndian, v); err != nil {
+            panic(err)
+        }
+    }
+    return b.Bytes()
+}
+
+func box(t string, payload ...[]byte) []byte {
+    p := bytes.Join(payload, nil)
+    return append(be(uint32(8 + len(p)), []byte(t)), p...)
+}
+
+func fullBox(t string, version uint8, flags uint32, payload ...[]byte) []byte {
+    return box(t, append([][]byte{be(uint32(version) << 24 | flags)}, payload...)...)
+}
+
+// The box of largesize, the size is 1 and the 64 bits size follows the type.
+func largeBox(t string, payload ...[]byte) []byte {
+    p := bytes.Join(payload, nil)
+    return append(be(uint32(1), []byte(t), uint64(16 + len(p))), p...)
+}
+
+// The matrix of identity, for mvhd and tkhd.
+var testMatrix = []int32{0x10000, 0, 0, 0, 0x10000, 0, 0, 0, 0x40000000}
+
+// The SPS of H.264 High profile level 3.1, 1920x1080 cropped from 1920x1088, 29.97fps.
+var testSps = []byte{0x67, 0x64, 0x00, 0x1f, 0xac, 0xd9, 0x40, 0x78, 0x02, 0x27, 0xe5, 0xc0, 0x5a, 0x80, 0x80, 0x80,
+    0xa0, 0x00, 0x00, 0x7d, 0x20, 0x00, 0x1d, 0x4c, 0x10, 0x80}
+var testPps = []byte{0x68, 0xeb, 0xe3, 0xcb, 0x22, 0xc0}
+
+func avcC(sps, pps []byte) []byte {
+    return box("avcC", []byte{1, sps[1], sps[2], sps[3], 0xff, 0xe1}, be(uint16(len(sps))), sps,
+        []byte{1}, be(uint16(len(pps))), pps, []byte{0xfd, 0xf8, 0xf8, 0})
+}
+
+func visualEntry(t string, width, height uint16, children ...[]byte) []byte {
+    return box(t, make([]byte, 6), be(uint16(1)), make([]byte, 16),
+        be(width, height, uint32(0x00480000), uint32(0x00480000), uint32(0), uint16(1)),
+        make([]byte, 32), be(uint16(0x18), int16(-1)), bytes.Join(children, nil))
+}
+
+func audioEntry(t string, channels, sampleSize uint16, rate uint32, children ...[]byte) []byte {
+    return box(t, make([]byte, 6), be(uint16(1)), make([]byte, 8),
+        be(channels, sampleSize, uint16(0), uint16(0), rate << 16), bytes.Join(children, nil))
+}
+
+// The descriptor of esds, with the size in 4 bytes.
+func descriptor(tag uint8, payload ...[]byte) []byte {
+    p := bytes.Join(payload, nil)
+    return append([]byte{tag, 0x80, 0x80, 0x80, uint8(len(p))}, p...)
+}
+
+func esds(oti uint8, asc []byte) []byte {
+    dcd := descriptor(0x04, []byte{oti, 0x15}, make([]byte, 3), be(uint32(128000), uint32(128000)), descriptor(0x05, asc))
+    return fullBox("esds", 0, 0, descriptor(0x03, be(uint16(2), uint8(0)), dcd, descriptor(0x06, []byte{0x02})))
+}
+
+// The track for tests, the samples are written to mdat in chunks of samplesPerChunk, interleaved
+// with the chunks of other tracks.
+type testTrack struct {
+    id uint32
+    handler string
+    timescale uint32
+    entry []byte
+    samples [][]byte
+    delta uint32
+    samplesPerChunk int
+    // The optional boxes, the mhd is vmhd or smhd by handler when nil.
+    mhd []byte
+    edts []byte
+    dref []byte
+    ctts [][2]uint32
+    stss []uint32
+    co64 bool
+    width, height uint16
+}
+
+// Create the track of n samples, the sample i is i+1 bytes of the track id and i.
+func newTestTrack(id uint32, handler string, entry []byte, n int) *testTrack {
+    v := &testTrack{id: id, handler: handler, timescale: 1000, entry: entry, delta: 40, samplesPerChunk: 3}
+    for i := 0; i < n; i++ {
+        v.samples = append(v.samples, bytes.Repeat([]byte{uint8(id), uint8(i)}, i + 1))
+    }
+    return v
+}
+
+func (v *testTrack) duration() uint32 {
+    return uint32(len(v.samples)) * v.delta
+}
+
+// The chunks of samples, the last chunk maybe less.
+func (v *testTrack) chunks() (chunks [][][]byte) {
+    for i := 0; i < len(v.samples); i += v.samplesPerChunk {
+        end := i + v.samplesPerChunk
+        if end > len(v.samples) {
+            end = len(v.samples)
+        }
+        chunks = append(chunks, v.samples[i:end])
+    }
+    return
+}
+
+func (v *testTrack) trak(offsets []uint64) []byte {
+    tkhd := fullBox("tkhd", 0, 3, be(uint32(3600000000), uint32(3600000001), v.id, uint32(0), v.duration() * 1000 / v.timescale),
+        make([]byte, 8), be(int16(0), int16(0), int16(0x100), uint16(0)), be(testMatrix),
+        be(uint32(v.width) << 16, uint32(v.height) << 16))
+    lang := uint16('e' - 0x60) << 10 | uint16('n' - 0x60) << 5 | uint16('g' - 0x60)
+    mdhd := fullBox("mdhd", 0, 0, be(uint32(3600000000), uint32(3600000001), v.timescale, v.duration(), lang, uint16(0)))
+    hdlr := fullBox("hdlr", 0, 0, be(uint32(0), []byte(v.handler)), make([]byte, 12), []byte("Handler\x00"))
+
+    mhd := v.mhd
+    if mhd == nil && v.handler == "vide" {
+        mhd = fullBox("vmhd", 0, 1, make([]byte, 8))
+    } else if mhd == nil {
+        mhd = fullBox("smhd", 0, 0, make([]byte, 4))
+    }
+    dref := v.dref
+    if dref == nil {
+        dref = fullBox("dref", 0, 0, be(uint32(1)), fullBox("url ", 0, SRS_MP4_DATA_ENTRY_SELF_CONTAINED))
+    }
+
+    stbl := [][]byte{
+        fullBox("stsd", 0, 0, be(uint32(1)), v.entry),
+        fullBox("stts", 0, 0, be(uint32(1), uint32(len(v.samples)), v.delta)),
+    }
+    if v.ctts != nil {
+        stbl = append(stbl, fullBox("ctts", 0, 0, be(uint32(len(v.ctts)), v.ctts)))
+    }
+    if v.stss != nil {
+        stbl = append(stbl, fullBox("stss", 0, 0, be(uint32(len(v.stss)), v.stss)))
+    }
+    chunks := v.chunks()
+    stsc := [][3]uint32{{1, uint32(v.samplesPerChunk), 1}}
+    if last := len(chunks[len(chunks) - 1]); len(chunks) > 1 && last != v.samplesPerChunk {
+        stsc = append(stsc, [3]uint32{uint32(len(chunks)), uint32(last), 1})
+    }
+    stbl = append(stbl, fullBox("stsc", 0, 0, be(uint32(len(stsc)), stsc)))
+    sizes := []uint32{}
+    for _, sample := range v.samples {
+        sizes = append(sizes, uint32(len(sample)))
+    }
+    stbl = append(stbl, fullBox("stsz", 0, 0, be(uint32(0), uint32(len(sizes)), sizes)))
+    if v.co64 {
+        stbl = append(stbl, fullBox("co64", 0, 0, be(uint32(len(offsets)), offsets)))
+    } else {
+        stco := []uint32{}
+        for _, offset := range offsets {
+            stco = append(stco, uint32(offset))
+        }
+        stbl = append(stbl, fullBox("stco", 0, 0, be(uint32(len(stco)), stco)))
+    }
+
+    minf := box("minf", mhd, box("dinf", dref), box("stbl", stbl...))
+    return box("trak", tkhd, v.edts, box("mdia", mdhd, hdlr, minf))
+}
+
+// The moov of tracks, the offsets are the chunk offsets of each track.
+func moovBox(tracks []*testTrack, offsets [][]uint64) []byte {
+    var duration uint32
+    var traks [][]byte
+    for i, trak := range tracks {
+        traks = append(traks, trak.trak(offsets[i]))
+        if d := trak.duration() * 1000 / trak.timescale; d > duration {
+            duration = d
+        }
+    }
+    mvhd := fullBox("mvhd", 0, 0, be(uint32(3600000000), uint32(3600000001), uint32(1000), duration,
+        uint32(0x10000), uint16(0x100)), make([]byte, 10), be(testMatrix), make([]byte, 24), be(uint32(len(tracks) + 1)))
+    return box("moov", append([][]byte{mvhd}, traks...)...)
+}
+
+// The chunk offsets of track, when all chunks are written in sequence at pos.
+func (v *testTrack) offsetsAt(pos uint64) (offsets []uint64) {
+    for _, chunk := range v.chunks() {
+        offsets = append(offsets, pos)
+        pos += uint64(len(bytes.Join(chunk, nil)))
+    }
+    return
+}
+
+// Build the file of ftyp, moov and mdat, the moov is after mdat when moovAtEnd. The chunks of tracks
+// are interleaved in mdat, and the chunk offsets point to them.
+func buildFile(moovAtEnd bool, tracks ...*testTrack) []byte {
+    ftyp := box("ftyp", []byte("isom"), be(uint32(512)), []byte("isomiso2avc1mp41"))
+
+    // The chunks in mdat, the first chunk of all tracks, then the second, and so on.
+    var payload []byte
+    positions := make([][]int, len(tracks))
+    for i := 0; ; i++ {
+        var written bool
+        for j, trak := range tracks {
+            if chunks := trak.chunks(); i < len(chunks) {
+                positions[j] = append(positions[j], len(payload))
+                payload = append(payload, bytes.Join(chunks[i], nil)...)
+                written = true
+            }
+        }
+        if !written {
+            break
+        }
+    }
+    mdat := box("mdat", payload)
+
+    moov := func(start int) []byte {
+        offsets := make([][]uint64, len(tracks))
+        for i := range tracks {
+            for _, pos := range positions[i] {
+                offsets[i] = append(offsets[i], uint64(start + 8 + pos))
+            }
+        }
+        return moovBox(tracks, offsets)
+    }
+
+    if moovAtEnd {
+        return bytes.Join([][]byte{ftyp, mdat, moov(len(ftyp))}, nil)
+    }
+    start := len(ftyp) + len(moov(0))
+    return bytes.Join([][]byte{ftyp, moov(start), mdat}, nil)
+}
+
+// The avc1 video track of 10 samples, and the mp4a audio track of 15 samples.
+func newAvTracks() (video, audio *testTrack) {
+    video = newTestTrack(1, "vide", visualEntry("avc1", 1920, 1080, avcC(testSps, testPps)), 10)
+    video.width, video.height, video.stss = 1920, 1080, []uint32{1, 6}
+    audio = newTestTrack(2, "soun", audioEntry("mp4a", 2, 16, 48000, esds(SrsMp4ObjectTypeAac, []byte{0x11, 0x90})), 15)
+    audio.timescale, audio.delta, audio.samplesPerChunk = 48000, 1024, 4
+    return
+}
+
+// The file of an avc1 video and an mp4a audio track.
+func buildAvFile(moovAtEnd bool) []byte {
+    video, audio := newAvTracks()
+    return buildFile(moovAtEnd, video, audio)
+}
+
+func parseFile(t *testing.T, b []byte) *File {
+    t.Helper()
+    f, err := Parse(bytes.NewReader(b))
+    if err != nil {
+        t.Fatalf("parse failed, err is %v", err)
+    }
+    return f
+}
+
+func parseTracks(t *testing.T, b []byte) []*Mp4TrackBox {
+    t.Helper()
+    moov, err := parseFile(t, b).Moov()
+    if err != nil {
+        t.Fatalf("no moov, err is %v", err)
+    }
+    return moov.Tracks()
+}
@@ -0,0 +1,183 @@
+package mp4
+
+import (
+    "fmt"
+    "strings"
+)
+
+// Get the codecs parameter of RFC 6381 for the first sample entry, for example, avc1.64001f, mp4a.40.2
+// and hvc1.1.6.L93.B0, which is used by the manifest of HLS and DASH, and MediaSource.isTypeSupported.
+func (v *Mp4TrackBox) Codecs() (codecs string, err error) {
+    var stsd *Mp4SampleDescritionBox
+    if stsd, err = v.Stsd(); err != nil {
+        return
+    }
+    if len(stsd.Entries) == 0 {
+        return "", fmt.Errorf("no entry in stsd")
+    }
+
+    entry := stsd.Entries[0]
+    fourcc := FourCC(entry.Basic().BoxType)
+    switch entry := entry.(type) {
+    case *Mp4VisualSampleEntry:
+        return visualCodecs(fourcc, entry)
+    case *Mp4AudioSampleEntry:
+        return audioCodecs(fourcc, entry)
+    }
+    return strings.TrimSpace(fourcc), nil
+}
+
+func visualCodecs(fourcc string, entry *Mp4VisualSampleEntry) (codecs string, err error) {
+    switch entry.BoxType {
+    case SrsMp4BoxTypeAVC1:
+        // ISO_IEC_14496-15-AVC-format-2012.pdf, page 7, the profile_idc, constraint flags and level_idc.
+        var avcc *Mp4AvccBox
+        if avcc, err = entry.Avcc(); err != nil {
+            return
+        }
+        if len(avcc.AvcConfig) < 4 {
+            return "", fmt.Errorf("invalid avcc config %v bytes", len(avcc.AvcConfig))
+        }
+        return fmt.Sprintf("%v.%02x%02x%02x", fourcc, avcc.AvcProfileIndication, avcc.ProfileCompatibility, avcc.AvcLevelIndication), nil
+    case SrsMp4BoxTypeHVC1, SrsMp4BoxTypeHEV1:
+        var hvcc *Mp4HvccBox
+        if hvcc, err = entry.Hvcc(); err != nil {
+            return
+        }
+        return fmt.Sprintf("%v.%v", fourcc, hvcc.Codecs()), nil
+    case SrsMp4BoxTypeAV01:
+        var av1c *Mp4Av1cBox
+        if av1c, err = entry.Av1c(); err != nil {
+            return
+        }
+        return fmt.Sprintf("%v.%v", fourcc, av1c.Codecs()), nil
+    case SrsMp4BoxTypeVP09, SrsMp4BoxTypeVP08:
+        var vpcc *Mp4VpccBox
+        if vpcc, err = entry.Vpcc(); err != nil {
+            return
+        }
+        if vpcc.Version != 1 {
+            return "", fmt.Errorf("unsupported vpcc version %v", vpcc.Version)
+        }
+        // VP-Codec-ISO-Media-File-Format-Binding-v1.0.pdf, the profile, level and bit depth.
+        return fmt.Sprintf("%v.%02d.%02d.%02d", fourcc, vpcc.Profile, vpcc.Level, vpcc.BitDepth), nil
+    }
+    return strings.TrimSpace(fourcc), nil
+}
+
+func audioCodecs(fourcc string, entry *Mp4AudioSampleEntry) (codecs string, err error) {
+    switch entry.BoxType {
+    case SrsMp4BoxTypeMP4A:
+        // The objectTypeIndication in hex, and the audio object type of aac in decimal.
+        var esds *Mp4EsdsBox
+        if esds, err = entry.Esds(); err != nil {
+            return
+        }
+        oti := esds.ObjectTypeIndication()
+        codecs = fmt.Sprintf("%v.%02x", fourcc, oti)
+        if oti != SrsMp4ObjectTypeAac {
+            return
+        }
+        if asc, err := entry.Asc(); err == nil {
+            if aac, err := asc.AacConfig(); err == nil {
+                codecs = fmt.Sprintf("%v.%v", codecs, aac.SignalledObjectType())
+            }
+        }
+        return codecs, nil
+    case SrsMp4BoxTypeOPUS:
+        return "opus", nil
+    case SrsMp4BoxTypeFLAC:
+        return "flac", nil
+    }
+    return strings.TrimSpace(fourcc), nil
+}
+
+// Get the codecs parameter without the sample entry, for example, 1.6.L93.B0.
+// @see ISO_IEC_14496-15-AVC-format-2014.pdf, E.3 Codecs parameter for HEVC
+func (v *Mp4HvccBox) Codecs() string {
+    var b strings.Builder
+
+    // The profile space as A, B or C, with the profile_idc.
+    if v.GeneralProfileSpace > 0 {
+        b.WriteByte('A' + v.GeneralProfileSpace - 1)
+    }
+    fmt.Fprintf(&b, "%v", v.GeneralProfileIdc)
+
+    // The compatibility flags in reverse bit order, without leading zeros.
+    var flags uint32
+    for i := 0; i < 32; i++ {
+        flags |= (v.GeneralProfileCompatibilityFlags >> uint(i) & 0x01) << uint(31 - i)
+    }
+    fmt.Fprintf(&b, ".%X", flags)
+
+    tier := "L"
+    if v.GeneralTierFlag != 0 {
+        tier = "H"
+    }
+    fmt.Fprintf(&b, ".%v%v", tier, v.GeneralLevelIdc)
+
+    // The 6 bytes of constraint flags, the trailing zero bytes are omitted.
+    constraints := make([]uint8, 6)
+    for i := range constraints {
+        constraints[i] = uint8(v.GeneralConstraintIndicatorFlags >> uint(40 - 8 * i))
+    }
+    for len(constraints) > 0 && constraints[len(constraints) - 1] == 0 {
+        constraints = constraints[:len(constraints) - 1]
+    }
+    for _, c := range constraints {
+        fmt.Fprintf(&b, ".%X", c)
+    }
+    return b.String()
+}
+
+// Get the codecs parameter without the sample entry, for example, 0.04M.08.
+// @see av1-isobmff-v1.2.0.pdf, A.3 Codecs Parameter String
+func (v *Mp4Av1cBox) Codecs() string {
+    tier := "M"
+    if v.SeqTier0 != 0 {
+        tier = "H"
+    }
+    return fmt.Sprintf("%v.%02d%v.%02d", v.SeqProfile, v.SeqLevelIdx0, tier, v.BitDepth())
+}
+
+// Get the MIME type with the codecs parameter of all audio and video tracks, for example,
+// video/mp4; codecs="avc1.64001f,mp4a.40.2", the audio/mp4 for file without video.
+func (v *File) MimeType() (mime string, err error) {
+    var moov *Mp4MovieBox
+    if moov, err = v.Moov(); err != nil {
+        return
+    }
+
+    mime = "audio/mp4"
+    if moov.NbVideoTracks() > 0 {
+        mime = "video/mp4"
+    }
+    if ftyp, err := v.Ftyp(); err == nil && ftyp.MajorBrand == SrsMp4BoxBrandQT {
+        mime = "video/quicktime"
+    }
+
+    var codecs []string
+    for _, trak := range moov.Tracks() {
+        if t := trak.TrackType(); t != SrsMp4TrackTypeVideo && t != SrsMp4TrackTypeAudio {
+            continue
+        }
+
+        var c string
+        if c, err = trak.Codecs(); err != nil {
+            return
+        }
+
+        var exists bool
+        for _, codec := range codecs {
+            exists = exists || codec == c
+        }
+        if !exists {
+            codecs = append(codecs, c)
+        }
+    }
+
+    if len(codecs) > 0 {
+        mime = fmt.Sprintf("%v; codecs=\"%v\"", mime, strings.Join(codecs, ","))
+    }
+    return
+}
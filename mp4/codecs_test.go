@@ -0,0 +1,88 @@
+package mp4
+
+import (
+    "testing"
+)
+
+func TestCodecs(t *testing.T) {
+    cases := []struct {
+        name string
+        handler string
+        entry []byte
+        codecs string
+    }{
+        {"avc1", "vide", visualEntry("avc1", 1920, 1080, avcC(testSps, testPps)), "avc1.64001f"},
+        {"avc3", "vide", visualEntry("avc3", 1920, 1080, avcC(testSps, testPps)), "avc3"},
+        {"hvc1", "vide", visualEntry("hvc1", 1920, 1080, testHvcc), "hvc1.1.6.L93.90"},
+        {"hev1", "vide", visualEntry("hev1", 1920, 1080, testHvcc), "hev1.1.6.L93.90"},
+        {"av01", "vide", visualEntry("av01", 1920, 1080, box("av1C", []byte{0x81, 0x08, 0x0c, 0x00})), "av01.0.08M.08"},
+        {"av01 high 10 bits", "vide", visualEntry("av01", 1920, 1080, box("av1C", []byte{0x81, 0x2d, 0xcc, 0x00})), "av01.1.13H.10"},
+        {"vp09", "vide", visualEntry("vp09", 1920, 1080, fullBox("vpcC", 1, 0, []byte{2, 31, 0xa4, 1, 1, 1}, be(uint16(0)))), "vp09.02.31.10"},
+        {"vp08", "vide", visualEntry("vp08", 640, 360, fullBox("vpcC", 1, 0, []byte{0, 10, 0x82, 2, 2, 2}, be(uint16(0)))), "vp08.00.10.08"},
+        {"mp4a aac", "soun", audioEntry("mp4a", 2, 16, 48000, esds(SrsMp4ObjectTypeAac, []byte{0x11, 0x90})), "mp4a.40.2"},
+        {"mp4a he-aac", "soun", audioEntry("mp4a", 2, 16, 24000, esds(SrsMp4ObjectTypeAac, []byte{0x13, 0x10, 0x56, 0xe5, 0x98})), "mp4a.40.5"},
+        {"mp4a he-aacv2", "soun", audioEntry("mp4a", 1, 16, 24000, esds(SrsMp4ObjectTypeAac, []byte{0xeb, 0x09, 0x88, 0x00})), "mp4a.40.29"},
+        // The MP3 of MPEG-1, the objectTypeIndication only.
+        {"mp4a mp3", "soun", audioEntry("mp4a", 2, 16, 44100, esds(0x6b, nil)), "mp4a.6b"},
+        {"opus", "soun", audioEntry("Opus", 2, 16, 48000, box("dOps", []byte{0, 2}, be(uint16(312), uint32(48000), int16(0)), []byte{0})), "opus"},
+        {"ac-3", "soun", audioEntry("ac-3", 2, 16, 48000, box("dac3", []byte{0x10, 0x3d, 0xe0})), "ac-3"},
+        {"ec-3", "soun", audioEntry("ec-3", 2, 16, 48000, box("dec3", []byte{0x14, 0x00, 0x20, 0x0f, 0x00})), "ec-3"},
+        {"flac", "soun", audioEntry("fLaC", 2, 16, 44100, fullBox("dfLa", 0, 0, flacBlock(true, 0, flacStreamInfo(44100, 2, 16, 0)))), "flac"},
+        {"ipcm", "soun", audioEntry("ipcm", 2, 24, 48000, fullBox("pcmC", 0, 0, []byte{1, 24})), "ipcm"},
+    }
+    for _, c := range cases {
+        trak := parseTracks(t, buildFile(false, newTestTrack(1, c.handler, c.entry, 3)))[0]
+        if codecs, err := trak.Codecs(); err != nil || codecs != c.codecs {
+            t.Errorf("%v: codecs %v, expect %v, err is %v", c.name, codecs, c.codecs, err)
+        }
+    }
+
+    for name, entry := range map[string][]byte{
+        "avc1 without avcC": visualEntry("avc1", 1920, 1080),
+        "vpcC of version 0": visualEntry("vp09", 1920, 1080, fullBox("vpcC", 0, 0, []byte{0, 10, 0x80, 0x06, 0x00})),
+        "mp4a without esds": audioEntry("mp4a", 2, 16, 48000),
+    } {
+        if codecs, err := parseTracks(t, buildFile(false, newTestTrack(1, "vide", entry, 3)))[0].Codecs(); err == nil {
+            t.Errorf("%v: codecs %v should fail", name, codecs)
+        }
+    }
+}
+
+func TestMimeType(t *testing.T) {
+    video, audio := newAvTracks()
+    // The second audio track of the same codecs is listed once.
+    audio2 := newTestTrack(3, "soun", audio.entry, 3)
+    opus := newTestTrack(4, "soun", audioEntry("Opus", 2, 16, 48000, box("dOps", []byte{0, 2}, be(uint16(312), uint32(48000), int16(0)), []byte{0})), 3)
+    hint := newTestTrack(5, "hint", visualEntry("rtp ", 0, 0), 3)
+
+    qt := buildFile(false, video, audio)
+    copy(qt[8:], "qt  ")
+
+    cases := []struct {
+        name string
+        b []byte
+        mime string
+    }{
+        {"av", buildAvFile(false), `video/mp4; codecs="avc1.64001f,mp4a.40.2"`},
+        {"audio only", buildFile(false, audio), `audio/mp4; codecs="mp4a.40.2"`},
+        {"dedup", buildFile(false, video, audio, audio2, opus), `video/mp4; codecs="avc1.64001f,mp4a.40.2,opus"`},
+        // The tracks other than video and audio are ignored.
+        {"hint", buildFile(false, audio, hint), `audio/mp4; codecs="mp4a.40.2"`},
+        {"quicktime", qt, `video/quicktime; codecs="avc1.64001f,mp4a.40.2"`},
+    }
+    for _, c := range cases {
+        f := parseFile(t, c.b)
+        if mime, err := f.MimeType(); err != nil || mime != c.mime {
+            t.Errorf("%v: mime %v, expect %v, err is %v", c.name, mime, c.mime, err)
+        }
+        if v := NewFileJSON(f); v.MimeType != c.mime {
+            t.Errorf("%v: summary mime %v", c.name, v.MimeType)
+        }
+    }
+
+    // The codecs of track is required.
+    broken := newTestTrack(1, "vide", visualEntry("avc1", 1920, 1080), 3)
+    if mime, err := parseFile(t, buildFile(false, broken)).MimeType(); err == nil {
+        t.Errorf("mime %v should fail", mime)
+    }
+}
@@ -0,0 +1,125 @@
+package mp4
+
+import (
+    "fmt"
+    "math"
+)
+
+// The edit of track, which maps a part of media timeline to the presentation timeline, all times are
+// in the timescale of mdhd, see Mp4TrackBox.Edits.
+type Mp4Edit struct {
+    // The start time and duration in the presentation timeline.
+    Start int64 `json:"start"`
+    Duration int64 `json:"duration"`
+    // The start time in the media timeline, -1 for the empty edit.
+    MediaTime int64 `json:"media_time"`
+    // The rate in 16.16, 0 for the dwell, which presents the media at media time for the duration.
+    MediaRate int32 `json:"media_rate"`
+}
+
+// Resolve the edit list to the edits in the presentation timeline, the movieTimeScale is the
+// timescale of mvhd, in which the segment duration is.
+// @remark The track without elst presents the whole media from 0 at rate 1.
+// @remark The edit of segment duration 0 presents the media to the end.
+// @remark Fails when the end of edits overflows the int64, for example, the corrupted segment duration.
+func (v *Mp4TrackBox) Edits(movieTimeScale uint32) (edits []*Mp4Edit, err error) {
+    var mdhd *Mp4MediaHeaderBox
+    if mdhd, err = v.Mdhd(); err != nil {
+        return
+    }
+
+    elst, err := v.Elst()
+    if err != nil {
+        if mdhd.Duration > math.MaxInt64 {
+            return nil, fmt.Errorf("media duration %v overflow", mdhd.Duration)
+        }
+        return []*Mp4Edit{{Duration: int64(mdhd.Duration), MediaRate: 0x10000}}, nil
+    }
+    if movieTimeScale == 0 {
+        return nil, fmt.Errorf("invalid movie timescale %v", movieTimeScale)
+    }
+
+    var start int64
+    for i, entry := range elst.Entries {
+        edit := &Mp4Edit{
+            Start: start,
+            MediaTime: entry.MediaTime,
+            MediaRate: int32(entry.MediaRateInteger) << 16 | int32(uint16(entry.MediaRateFraction)),
+        }
+
+        // The rescale overflows to the max uint64, which is over the int64 too.
+        duration := rescale(entry.SegmentDuration, uint64(movieTimeScale), uint64(mdhd.TimeScale))
+        // The media to the end, in the presentation timeline.
+        if entry.SegmentDuration == 0 && edit.MediaTime >= 0 && edit.MediaRate > 0 && mdhd.Duration > uint64(edit.MediaTime) {
+            duration = rescale(mdhd.Duration - uint64(edit.MediaTime), uint64(edit.MediaRate), 0x10000)
+        }
+        if duration > uint64(math.MaxInt64 - start) {
+            return nil, fmt.Errorf("edit %v duration %v at %v overflow", i, entry.SegmentDuration, start)
+        }
+        edit.Duration = int64(duration)
+
+        edits = append(edits, edit)
+        start += edit.Duration
+    }
+    return
+}
+
+// Map the media time, for example, the pts of sample, to the presentation time, both in the timescale
+// of mdhd, the movieTimeScale is the timescale of mvhd.
+// @return ok is false when the media time is not presented, for example, the priming samples of audio
+// before the media time of the first edit.
+func (v *Mp4TrackBox) PresentationTime(mediaTime int64, movieTimeScale uint32) (pts int64, ok bool, err error) {
+    var edits []*Mp4Edit
+    if edits, err = v.Edits(movieTimeScale); err != nil {
+        return
+    }
+
+    for _, edit := range edits {
+        if edit.MediaTime < 0 || mediaTime < edit.MediaTime {
+            continue
+        }
+
+        // The dwell presents only the media at media time.
+        if edit.MediaRate <= 0 {
+            if mediaTime == edit.MediaTime {
+                return edit.Start, true, nil
+            }
+            continue
+        }
+
+        // The end of edits never overflows, so the offset in the edit doesn't.
+        offset := rescale(uint64(mediaTime - edit.MediaTime), uint64(edit.MediaRate), 0x10000)
+        if offset < uint64(edit.Duration) {
+            return edit.Start + int64(offset), true, nil
+        }
+    }
+    return
+}
+
+// Get the duration in the presentation timeline, in the timescale of mdhd, which is the sum of
+// edits, including the empty ones.
+func (v *Mp4TrackBox) PresentationDuration(movieTimeScale uint32) (duration uint64, err error) {
+    var edits []*Mp4Edit
+    if edits, err = v.Edits(movieTimeScale); err != nil {
+        return
+    }
+    for _, edit := range edits {
+        duration += uint64(edit.Duration)
+    }
+    return
+}
+
+// Get the offset to map the media time to the presentation time, by the first non-empty edit,
+// for example, the delay of empty edit is positive and the priming of encoder is negative.
+func (v *Mp4TrackBox) StartOffset(movieTimeScale uint32) (offset int64, err error) {
+    var edits []*Mp4Edit
+    if edits, err = v.Edits(movieTimeScale); err != nil {
+        return
+    }
+    for _, edit := range edits {
+        if edit.MediaTime >= 0 {
+            return edit.Start - edit.MediaTime, nil
+        }
+    }
+    return
+}
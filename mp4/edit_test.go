@@ -0,0 +1,180 @@
+package mp4
+
+import (
+    "math"
+    "testing"
+)
+
+// The edts of elst version 0, the entry is the segment duration, media time and media rate integer.
+func elstV0(entries ...[3]int32) []byte {
+    var payload []byte
+    for _, e := range entries {
+        payload = append(payload, be(uint32(e[0]), e[1], int16(e[2]), int16(0))...)
+    }
+    return box("edts", fullBox("elst", 0, 0, be(uint32(len(entries))), payload))
+}
+
+// The edts of elst version 1, the entry is the segment duration, media time and media rate integer.
+func elstV1(entries ...[3]int64) []byte {
+    var payload []byte
+    for _, e := range entries {
+        payload = append(payload, be(uint64(e[0]), e[1], int16(e[2]), int16(0))...)
+    }
+    return box("edts", fullBox("elst", 1, 0, be(uint32(len(entries))), payload))
+}
+
+func TestEdits(t *testing.T) {
+    cases := []struct {
+        name string
+        // The edts of the audio of 15 samples in 48000, 15360 in total, the timescale of mvhd is 1000.
+        edts []byte
+        // The start, duration, media time and media rate of edits.
+        edits [][4]int64
+        duration uint64
+        offset int64
+        // The media time to presentation time, -1 for not presented.
+        times [][2]int64
+    }{
+        {"no edit", nil, [][4]int64{{0, 15360, 0, 0x10000}}, 15360, 0,
+            [][2]int64{{0, 0}, {1024, 1024}, {15359, 15359}}},
+        // The audio starts after 100ms.
+        {"empty edit", elstV0([3]int32{100, -1, 1}, [3]int32{320, 0, 1}),
+            [][4]int64{{0, 4800, -1, 0x10000}, {4800, 15360, 0, 0x10000}}, 20160, 4800,
+            [][2]int64{{0, 4800}, {1024, 5824}, {15359, 20159}, {15360, -1}}},
+        // The priming of 1024 samples, the segment duration 0 presents the media to the end.
+        {"priming", elstV1([3]int64{0, 1024, 1}),
+            [][4]int64{{0, 14336, 1024, 0x10000}}, 14336, -1024,
+            [][2]int64{{0, -1}, {1023, -1}, {1024, 0}, {2048, 1024}}},
+        {"priming v0", elstV0([3]int32{298, 1024, 1}),
+            [][4]int64{{0, 14304, 1024, 0x10000}}, 14304, -1024,
+            [][2]int64{{1024, 0}, {15327, 14303}, {15328, -1}}},
+        // The segment duration of 64 bits, which is longer than the media.
+        {"v1 long", elstV1([3]int64{1 << 33, 0, 1}),
+            [][4]int64{{0, (1 << 33) * 48, 0, 0x10000}}, (1 << 33) * 48, 0,
+            [][2]int64{{15359, 15359}}},
+        // The dwell of rate 0 presents the media time 2048 for 100ms, then the media time 0 for 100ms.
+        {"dwell", elstV0([3]int32{100, 2048, 0}, [3]int32{100, 0, 0}),
+            [][4]int64{{0, 4800, 2048, 0}, {4800, 4800, 0, 0}}, 9600, -2048,
+            [][2]int64{{2048, 0}, {0, 4800}, {1024, -1}}},
+    }
+
+    for _, c := range cases {
+        _, audio := newAvTracks()
+        audio.edts = c.edts
+        moov, err := parseFile(t, buildFile(false, audio)).Moov()
+        if err != nil {
+            t.Fatal(err)
+        }
+        trak := moov.Tracks()[0]
+
+        edits, err := trak.Edits(1000)
+        if err != nil || len(edits) != len(c.edits) {
+            t.Errorf("%v: edits %v, err is %v", c.name, len(edits), err)
+            continue
+        }
+        for i, edit := range edits {
+            if got := [4]int64{edit.Start, edit.Duration, edit.MediaTime, int64(edit.MediaRate)}; got != c.edits[i] {
+                t.Errorf("%v: edit %v is %v, expect %v", c.name, i, got, c.edits[i])
+            }
+        }
+
+        if duration, err := trak.PresentationDuration(1000); err != nil || duration != c.duration {
+            t.Errorf("%v: duration %v, expect %v, err is %v", c.name, duration, c.duration, err)
+        }
+        if offset, err := trak.StartOffset(1000); err != nil || offset != c.offset {
+            t.Errorf("%v: start offset %v, expect %v, err is %v", c.name, offset, c.offset, err)
+        }
+        for _, tm := range c.times {
+            pts, ok, err := trak.PresentationTime(tm[0], 1000)
+            if err != nil || ok != (tm[1] >= 0) || (ok && pts != tm[1]) {
+                t.Errorf("%v: media time %v is %v, ok=%v, expect %v, err is %v", c.name, tm[0], pts, ok, tm[1], err)
+            }
+        }
+
+        // The summary reports the duration after edits, and the duration of media.
+        if v := NewTrackJSON(moov, trak); v.Duration != c.duration || v.MediaDuration != 15360 || v.StartOffset != c.offset {
+            t.Errorf("%v: summary duration %v, media %v, offset %v", c.name, v.Duration, v.MediaDuration, v.StartOffset)
+        }
+    }
+
+    // The segment duration is in the timescale of mvhd, which is required.
+    _, audio := newAvTracks()
+    audio.edts = elstV0([3]int32{100, 0, 1})
+    if _, err := parseTracks(t, buildFile(false, audio))[0].Edits(0); err == nil {
+        t.Error("the edits of movie timescale 0 should fail")
+    }
+}
+
+func TestEditsOverflow(t *testing.T) {
+    // The audio of 15360 in 48000, the segment duration is in the movie timescale.
+    trak := func(edts []byte) (*Mp4MovieBox, *Mp4TrackBox) {
+        _, audio := newAvTracks()
+        audio.edts = edts
+        moov, err := parseFile(t, buildFile(false, audio)).Moov()
+        if err != nil {
+            t.Fatal(err)
+        }
+        return moov, moov.Tracks()[0]
+    }
+
+    cases := []struct {
+        name string
+        edts []byte
+        movieTimeScale uint32
+    }{
+        // The segment duration of 64 bits over the int64.
+        {"segment over int64", elstV1([3]int64{-1, 0, 1}), 48000},
+        {"segment of max int64", elstV1([3]int64{math.MaxInt64, 0, 1}, [3]int64{1, 0, 1}), 48000},
+        // The rescale to the timescale of mdhd overflows the 64 bits.
+        {"rescale", elstV1([3]int64{1 << 62, 0, 1}), 1000},
+        // The end of edits overflows.
+        {"sum", elstV1([3]int64{1 << 62, 0, 1}, [3]int64{1 << 62, 0, 1}), 48000},
+    }
+    for _, c := range cases {
+        moov, trak := trak(c.edts)
+        if edits, err := trak.Edits(c.movieTimeScale); err == nil {
+            t.Errorf("%v: edits %v should fail", c.name, edits)
+        }
+        if duration, err := trak.PresentationDuration(c.movieTimeScale); err == nil {
+            t.Errorf("%v: duration %v should fail", c.name, duration)
+        }
+        if _, _, err := trak.PresentationTime(0, c.movieTimeScale); err == nil {
+            t.Errorf("%v: presentation time should fail", c.name)
+        }
+        // The summary falls back to the duration of media.
+        if c.movieTimeScale == 1000 {
+            if v := NewTrackJSON(moov, trak); v.Duration != 15360 || v.MediaDuration != 15360 {
+                t.Errorf("%v: summary duration %v, media %v", c.name, v.Duration, v.MediaDuration)
+            }
+        }
+    }
+
+    // The long media of 1<<60, presented to the end at rate 0.5, which overflows the int64 when multiplied by 0x10000.
+    half := box("edts", fullBox("elst", 1, 0, be(uint32(1)), be(uint64(0), int64(0), int16(0), int16(-0x8000))))
+    _, long := trak(half)
+    mdhd, _ := long.Mdhd()
+    mdhd.Duration = 1 << 60
+    if duration, err := long.PresentationDuration(1000); err != nil || duration != 1 << 61 {
+        t.Errorf("long media duration %v, err is %v", duration, err)
+    }
+    if pts, ok, err := long.PresentationTime(1 << 59, 1000); err != nil || !ok || pts != 1 << 60 {
+        t.Errorf("long media pts %v, ok=%v, err is %v", pts, ok, err)
+    }
+
+    // The rate of 1/65536 presents the media 65536 times longer, which overflows.
+    slow := box("edts", fullBox("elst", 1, 0, be(uint32(1)), be(uint64(0), int64(0), int16(0), int16(1))))
+    _, long = trak(slow)
+    mdhd, _ = long.Mdhd()
+    mdhd.Duration = 1 << 60
+    if duration, err := long.PresentationDuration(1000); err == nil {
+        t.Errorf("slow media duration %v should fail", duration)
+    }
+
+    // The media duration over int64 without edits.
+    _, long = trak(nil)
+    mdhd, _ = long.Mdhd()
+    mdhd.Duration = 1 << 63
+    if edits, err := long.Edits(1000); err == nil {
+        t.Errorf("edits %v should fail", edits[0])
+    }
+}
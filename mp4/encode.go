@@ -0,0 +1,97 @@
+package mp4
+
+import (
+    "fmt"
+    "io"
+    ol "github.com/ossrs/go-oryx-lib/logger"
+)
+
+// Get the entire size of box, the header, fields and all contained boxes.
+func NbBytes(box Box) uint64 {
+    size := uint64(box.NbHeader())
+    for _, child := range Contained(box) {
+        size += NbBytes(child)
+    }
+    return size
+}
+
+// Update the size of box and its contained boxes, for the fields maybe changed.
+// The box of largesize or to the end of file is kept, and the small size is changed to
+// the largesize when overflow.
+func UpdateSize(box Box) {
+    for _, child := range Contained(box) {
+        UpdateSize(child)
+    }
+
+    b := box.Basic()
+    size := NbBytes(box)
+    if b.SmallSize == SRS_MP4_USE_LARGE_SIZE || b.SmallSize == SRS_MP4_EOF_SIZE {
+        b.LargeSize = size
+        return
+    }
+    if size > 0xffffffff {
+        b.SmallSize = SRS_MP4_USE_LARGE_SIZE
+        b.LargeSize = NbBytes(box)
+        return
+    }
+    b.SmallSize = uint32(size)
+}
+
+// Encode the box and its contained boxes to w, the size is updated before encoding.
+// The payload of mdat, udta and unknown boxes, which is skipped when decoding, is copied
+// from src, the file the box is decoded from, see PayloadBox.
+func Encode(w io.Writer, box Box, src io.ReaderAt) (err error) {
+    UpdateSize(box)
+    return encode(w, box, src)
+}
+
+func encode(w io.Writer, box Box, src io.ReaderAt) (err error) {
+    if err = box.EncodeHeader(w); err != nil {
+        ol.E(nil, fmt.Sprintf("encode box %v header failed, err is %v", FourCC(box.Basic().BoxType), err))
+        return
+    }
+
+    if pb, ok := box.(PayloadBox); ok {
+        if err = copyPayload(w, pb, src); err != nil {
+            return
+        }
+    }
+
+    for _, child := range Contained(box) {
+        if err = encode(w, child, src); err != nil {
+            return
+        }
+    }
+    return
+}
+
+// Copy the payload of box from src, without reading it into memory.
+func copyPayload(w io.Writer, box PayloadBox, src io.ReaderAt) (err error) {
+    offset, size := box.Payload()
+    if size <= 0 {
+        return
+    }
+    if src == nil {
+        return fmt.Errorf("no source for the payload of %v, offset=%v, size=%v", FourCC(box.Basic().BoxType), offset, size)
+    }
+
+    var n int64
+    if n, err = io.Copy(w, io.NewSectionReader(src, int64(offset), int64(size))); err == nil && n < int64(size) {
+        err = io.ErrUnexpectedEOF
+    }
+    if err != nil {
+        ol.E(nil, fmt.Sprintf("copy payload of %v at %v failed, size=%v, copied=%v, err is %v", FourCC(box.Basic().BoxType), offset, size, n, err))
+        return
+    }
+    return
+}
+
+// Encode all top-level boxes to w, the payloads are copied from src, see Encode.
+func (v *File) Encode(w io.Writer, src io.ReaderAt) (err error) {
+    for _, box := range v.Boxes {
+        if err = Encode(w, box, src); err != nil {
+            return
+        }
+    }
+    return
+}
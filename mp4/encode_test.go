@@ -0,0 +1,204 @@
+package mp4
+
+import (
+    "bytes"
+    "testing"
+)
+
+// The track of the fixture, all boxes of stbl are given, the sample tables are not resolved.
+func fixtureTrak(id uint32, handler string, mhd, edts []byte, stbl ...[]byte) []byte {
+    tkhd := fullBox("tkhd", 0, 3, be(uint32(0), uint32(0), id, uint32(0), uint32(1000)),
+        make([]byte, 8), be(int16(0), int16(0), int16(0x100), uint16(0)), be(testMatrix), be(uint32(0), uint32(0)))
+    mdhd := fullBox("mdhd", 0, 0, be(uint32(0), uint32(0), uint32(1000), uint32(1000), uint16(0x55c4), uint16(0)))
+    hdlr := fullBox("hdlr", 0, 0, be(uint32(0), []byte(handler)), make([]byte, 12), []byte("Handler\x00"))
+    dref := fullBox("dref", 0, 0, be(uint32(2)), fullBox("url ", 0, SRS_MP4_DATA_ENTRY_SELF_CONTAINED),
+        fullBox("urn ", 0, SRS_MP4_DATA_ENTRY_SELF_CONTAINED, []byte("urn:test\x00")))
+    minf := box("minf", mhd, box("dinf", dref), box("stbl", stbl...))
+    return box("trak", tkhd, edts, box("mdia", mdhd, hdlr, minf))
+}
+
+// The common sample table of one sample, without stsd.
+func fixtureStbl(entry []byte) [][]byte {
+    return [][]byte{
+        fullBox("stsd", 0, 0, be(uint32(1)), entry),
+        fullBox("stts", 0, 0, be(uint32(1), uint32(1), uint32(1000))),
+        fullBox("stsc", 0, 0, be(uint32(1), uint32(1), uint32(1), uint32(1))),
+        fullBox("stsz", 0, 0, be(uint32(0), uint32(1), uint32(4))),
+        fullBox("stco", 0, 0, be(uint32(1), uint32(8))),
+    }
+}
+
+// The file of all box types, each field is not default, so the encoder must write them back.
+func buildFixture() []byte {
+    vmhd := fullBox("vmhd", 0, 1, be(uint16(0), uint16(1), uint16(2), uint16(3)))
+    smhd := fullBox("smhd", 0, 0, be(int16(-0x100), uint16(0)))
+    elst0 := box("edts", fullBox("elst", 0, 0, be(uint32(2), uint32(100), int32(-1), int16(1), int16(0),
+        uint32(900), int32(0), int16(1), int16(0))))
+    elst1 := box("edts", fullBox("elst", 1, 0, be(uint32(1), uint64(1) << 33, int64(1024), int16(1), int16(0))))
+
+    hvcC := box("hvcC", []byte{1, 0x01, 0x60, 0, 0, 0, 0x90, 0, 0, 0, 0, 0, 93, 0xf0, 0, 0xfc, 0xfd, 0xf8, 0xf8, 0, 0, 0x0f, 1},
+        []byte{0xa0}, be(uint16(1), uint16(4)), []byte{0x40, 0x01, 0x0c, 0x01})
+    av1C := box("av1C", []byte{0x81, 0x08, 0x0c, 0x00}, []byte{0x0a, 0x0b, 0x00, 0x00, 0x00, 0x42})
+    vpcC := fullBox("vpcC", 1, 0, []byte{2, 31, 0xa4, 1, 1, 1}, be(uint16(2)), []byte{0xaa, 0xbb})
+    vpcC0 := fullBox("vpcC", 0, 0, []byte{0, 10, 0x80, 0x06, 0x00})
+    dOps := box("dOps", []byte{0, 2}, be(uint16(312), uint32(48000), int16(-3)), []byte{0})
+    dOps1 := box("dOps", []byte{0, 3}, be(uint16(312), uint32(48000), int16(0)), []byte{1, 2, 1, 0, 2, 1})
+    dac3 := box("dac3", []byte{0x10, 0x3d, 0xe0})
+    dec3 := box("dec3", []byte{0x0c, 0x00, 0x20, 0x0f, 0x00})
+    streaminfo := be(uint16(4096), uint16(4096), []byte{0, 0, 0x10, 0, 0x20, 0}, uint64(0x0bb8_0170_0000_0000), make([]byte, 16))
+    dfLa := fullBox("dfLa", 0, 0, be(uint32(1) << 31 | uint32(len(streaminfo))), streaminfo)
+    pcmC := fullBox("pcmC", 0, 0, []byte{1, 24})
+    // The QuickTime sound description of version 1 and 2.
+    twos := box("twos", make([]byte, 6), be(uint16(1)), be(uint16(1), uint16(0), uint32(0)),
+        be(uint16(2), uint16(16), int16(-2), uint16(0), uint32(44100) << 16), be(uint32(1), uint32(2), uint32(4), uint32(2)))
+    lpcm := box("lpcm", make([]byte, 6), be(uint16(1)), be(uint16(2), uint16(0), uint32(0)),
+        be(uint16(3), uint16(16), int16(-2), uint16(0), uint32(0x10000)),
+        be(uint32(72), float64(48000), uint32(2), uint32(0x7f000000), uint32(24), uint32(12), uint32(6), uint32(1)))
+
+    entries := [][]byte{
+        visualEntry("avc1", 1920, 1080, avcC(testSps, testPps)),
+        visualEntry("hvc1", 1920, 1080, hvcC),
+        visualEntry("hev1", 1280, 720, hvcC),
+        visualEntry("av01", 1920, 1080, av1C),
+        visualEntry("vp09", 1920, 1080, vpcC),
+        visualEntry("vp08", 640, 360, vpcC0),
+        audioEntry("mp4a", 2, 16, 48000, esds(SrsMp4ObjectTypeAac, []byte{0x11, 0x90})),
+        audioEntry("Opus", 2, 16, 48000, dOps),
+        audioEntry("Opus", 3, 16, 48000, dOps1),
+        audioEntry("ac-3", 2, 16, 48000, dac3),
+        audioEntry("ec-3", 6, 16, 48000, dec3),
+        audioEntry("fLaC", 2, 16, 48000, dfLa),
+        audioEntry("ipcm", 2, 24, 48000, pcmC),
+        audioEntry("fpcm", 2, 32, 48000, fullBox("pcmC", 0, 0, []byte{0, 32})),
+        audioEntry("sowt", 2, 16, 44100),
+        twos,
+        lpcm,
+    }
+
+    var traks [][]byte
+    for i, entry := range entries {
+        handler, mhd, edts := "vide", vmhd, elst0
+        if i >= 6 {
+            handler, mhd, edts = "soun", smhd, elst1
+        }
+        traks = append(traks, fixtureTrak(uint32(i + 1), handler, mhd, edts, fixtureStbl(entry)...))
+    }
+
+    // The tables of sample, in the tracks of hint, subtitle and meta.
+    hint := fixtureTrak(100, "hint", fullBox("hmhd", 0, 0, be(uint16(1400), uint16(1200), uint32(8000), uint32(6000), uint32(0))), nil,
+        fullBox("stsd", 0, 0, be(uint32(0))),
+        fullBox("stts", 0, 0, be(uint32(2), uint32(3), uint32(10), uint32(1), uint32(20))),
+        fullBox("ctts", 0, 0, be(uint32(2), uint32(2), uint32(20), uint32(2), uint32(0))),
+        fullBox("stss", 0, 0, be(uint32(2), uint32(1), uint32(3))),
+        fullBox("stsc", 0, 0, be(uint32(2), uint32(1), uint32(2), uint32(1), uint32(2), uint32(1), uint32(1))),
+        fullBox("stz2", 0, 0, make([]byte, 3), []byte{4}, be(uint32(3)), []byte{0x12, 0x30}),
+        fullBox("co64", 0, 0, be(uint32(2), uint64(1) << 32, uint64(2) << 32)))
+    sbtl := fixtureTrak(101, "sbtl", fullBox("sthd", 0, 0), nil,
+        fullBox("stsd", 0, 0, be(uint32(0))),
+        fullBox("ctts", 1, 0, be(uint32(1), uint32(2), int32(-10))),
+        fullBox("stz2", 0, 0, make([]byte, 3), []byte{8}, be(uint32(2)), []byte{1, 2}))
+    meta := fixtureTrak(102, "meta", fullBox("nmhd", 0, 0), nil,
+        fullBox("stsd", 0, 0, be(uint32(0))),
+        fullBox("stz2", 0, 0, make([]byte, 3), []byte{16}, be(uint32(2)), be(uint16(1000), uint16(2000))))
+    traks = append(traks, hint, sbtl, meta)
+
+    mvhd := fullBox("mvhd", 1, 0, be(uint64(3600000000), uint64(3600000001), uint32(1000), uint64(1) << 33,
+        uint32(0x10000), uint16(0x100)), make([]byte, 10), be(testMatrix), make([]byte, 24), be(uint32(103)))
+    udta := box("udta", box("meta", []byte("user data")))
+    moov := box("moov", append(append([][]byte{mvhd}, traks...), udta)...)
+
+    uuid := append(be(uint32(8 + 16 + 4), []byte("uuid")), append(bytes.Repeat([]byte{0x5a}, 16), []byte("uuid")...)...)
+    return bytes.Join([][]byte{
+        box("ftyp", []byte("isom"), be(uint32(512)), []byte("isomiso2avc1mp41")),
+        moov,
+        box("free", make([]byte, 16)),
+        uuid,
+        largeBox("mdat", bytes.Repeat([]byte{0xcd}, 64)),
+        be(uint32(0), []byte("mdat")), bytes.Repeat([]byte{0xef}, 64),
+    }, nil)
+}
+
+// Walk the box and its contained boxes, and the entries of stsd, depth first.
+func walkBoxes(boxes []Box, fn func(box Box)) {
+    for _, box := range boxes {
+        fn(box)
+        if stsd, ok := box.(*Mp4SampleDescritionBox); ok {
+            walkBoxes(stsd.Entries, fn)
+        }
+        walkBoxes(box.Basic().Boxes, fn)
+    }
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+    b := buildFixture()
+    f := parseFile(t, b)
+
+    // All box types are decoded by the box of its type, none is unknown.
+    types := map[string]bool{}
+    walkBoxes(f.Boxes, func(box Box) {
+        types[FourCC(box.Basic().BoxType)] = true
+        if _, ok := box.(*Mp4FreeSpaceBox); ok && box.Basic().BoxType != SrsMp4BoxTypeFREE && box.Basic().BoxType != SrsMp4BoxTypeUUID {
+            t.Errorf("box %v is unknown", FourCC(box.Basic().BoxType))
+        }
+    })
+    for _, bt := range []string{"ftyp", "moov", "mvhd", "trak", "tkhd", "edts", "elst", "mdia", "mdhd", "hdlr", "minf",
+        "vmhd", "smhd", "hmhd", "sthd", "nmhd", "dinf", "dref", "url ", "urn ", "stbl", "stsd",
+        "avc1", "avcC", "hvc1", "hev1", "hvcC", "av01", "av1C", "vp09", "vp08", "vpcC",
+        "mp4a", "esds", "Opus", "dOps", "ac-3", "dac3", "ec-3", "dec3", "fLaC", "dfLa", "ipcm", "fpcm", "pcmC",
+        "sowt", "twos", "lpcm", "stts", "ctts", "stss", "stsc", "stsz", "stz2", "stco", "co64",
+        "udta", "free", "uuid", "mdat"} {
+        if !types[bt] {
+            t.Errorf("no box %v in the fixture", bt)
+        }
+    }
+
+    var w bytes.Buffer
+    if err := f.Encode(&w, bytes.NewReader(b)); err != nil {
+        t.Fatal(err)
+    }
+    if !bytes.Equal(w.Bytes(), b) {
+        for i := range b {
+            if i >= w.Len() || w.Bytes()[i] != b[i] {
+                t.Fatalf("encode %v bytes, expect %v, differs at %v", w.Len(), len(b), i)
+            }
+        }
+        t.Fatalf("encode %v bytes, expect %v", w.Len(), len(b))
+    }
+
+    // Each box is encoded to its own bytes.
+    walkBoxes(f.Boxes, func(box Box) {
+        if _, ok := box.(PayloadBox); ok {
+            return
+        }
+        var w bytes.Buffer
+        start, size := box.Basic().StartPos, int(box.Basic().Size())
+        if err := Encode(&w, box, bytes.NewReader(b)); err != nil || !bytes.Equal(w.Bytes(), b[start:start + size]) {
+            t.Errorf("box %v at %v is not identical, err is %v", FourCC(box.Basic().BoxType), start, err)
+        }
+    })
+}
+
+func TestEncodeTrailingBytes(t *testing.T) {
+    tail := []byte{0xde, 0xad, 0xbe, 0xef, 0x01}
+    mvhd := fullBox("mvhd", 0, 0, be(uint32(0), uint32(0), uint32(1000), uint32(1000), uint32(0x10000), uint16(0x100)),
+        make([]byte, 10), be(testMatrix), make([]byte, 24), be(uint32(2)), tail)
+    vpcC := fullBox("vpcC", 1, 0, []byte{0, 10, 0x80, 1, 1, 1}, be(uint16(0)), tail)
+    trak := fixtureTrak(1, "vide", fullBox("vmhd", 0, 1, make([]byte, 8)), nil, fixtureStbl(visualEntry("vp09", 640, 360, vpcC))...)
+    b := bytes.Join([][]byte{box("ftyp", []byte("isom"), be(uint32(0))), box("moov", mvhd, trak)}, nil)
+
+    f := parseFile(t, b)
+    if moov, err := f.Moov(); err != nil {
+        t.Fatal(err)
+    } else if mvhd, err := moov.Mvhd(); err != nil || mvhd.NextTrackId != 2 || len(mvhd.Boxes) != 0 {
+        t.Errorf("mvhd %+v, err is %v", mvhd, err)
+    }
+    vpcc, err := f.Find("moov/trak/mdia/minf/stbl/stsd/vp09/vpcC")
+    if err != nil || vpcc.(*Mp4VpccBox).Level != 10 || len(vpcc.Basic().Boxes) != 0 {
+        t.Fatalf("vpcC %+v, err is %v", vpcc, err)
+    }
+
+    var w bytes.Buffer
+    if err := f.Encode(&w, bytes.NewReader(b)); err != nil || !bytes.Equal(w.Bytes(), b) {
+        t.Errorf("encode %v bytes, expect %v, the tails are lost, err is %v", w.Len(), len(b), err)
+    }
+}
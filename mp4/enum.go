@@ -1,4 +1,25 @@
-package main
+package mp4
+
+const (
+    SRS_MP4_EOF_SIZE = 0
+    SRS_MP4_USE_LARGE_SIZE = 1
+)
+
+const (
+    // The flag of url and urn, the media data is in the same file as the moov.
+    SRS_MP4_DATA_ENTRY_SELF_CONTAINED = 0x01
+
+    // The format flags of pcmC, the PCM samples are little-endian.
+    SRS_MP4_PCM_LITTLE_ENDIAN = 0x01
+    // The format specific flags of lpcm, kAudioFormatFlagIsBigEndian.
+    SRS_MP4_LPCM_BIG_ENDIAN = 0x02
+
+    // The block type of FLAC metadata, STREAMINFO.
+    SRS_MP4_FLAC_METADATA_STREAMINFO = 0
+
+    // The aspect_ratio_idc of avc VUI, the sar_width and sar_height are present, Table E-1.
+    SRS_AVC_ASPECT_RATIO_EXTENDED_SAR = 255
+)
 
 const (
     SrsMp4BoxTypeForbidden = 0x00
@@ -20,6 +41,9 @@ const (
     SrsMp4BoxTypeMINF = 0x6d696e66 // 'minf'
     SrsMp4BoxTypeVMHD = 0x766d6864 // 'vmhd'
     SrsMp4BoxTypeSMHD = 0x736d6864 // 'smhd'
+    SrsMp4BoxTypeHMHD = 0x686d6864 // 'hmhd'
+    SrsMp4BoxTypeSTHD = 0x73746864 // 'sthd'
+    SrsMp4BoxTypeNMHD = 0x6e6d6864 // 'nmhd'
     SrsMp4BoxTypeDINF = 0x64696e66 // 'dinf'
     SrsMp4BoxTypeURL  = 0x75726c20 // 'url '
     SrsMp4BoxTypeURN  = 0x75726e20 // 'urn '
@@ -36,8 +60,30 @@ const (
     SrsMp4BoxTypeSTZ2 = 0x73747a32 // 'stz2'
     SrsMp4BoxTypeAVC1 = 0x61766331 // 'avc1'
     SrsMp4BoxTypeAVCC = 0x61766343 // 'avcC'
+    SrsMp4BoxTypeHVC1 = 0x68766331 // 'hvc1'
+    SrsMp4BoxTypeHEV1 = 0x68657631 // 'hev1'
+    SrsMp4BoxTypeHVCC = 0x68766343 // 'hvcC'
+    SrsMp4BoxTypeAV01 = 0x61763031 // 'av01'
+    SrsMp4BoxTypeAV1C = 0x61763143 // 'av1C'
+    SrsMp4BoxTypeVP09 = 0x76703039 // 'vp09'
+    SrsMp4BoxTypeVP08 = 0x76703038 // 'vp08'
+    SrsMp4BoxTypeVPCC = 0x76706343 // 'vpcC'
     SrsMp4BoxTypeMP4A = 0x6d703461 // 'mp4a'
     SrsMp4BoxTypeESDS = 0x65736473 // 'esds'
+    SrsMp4BoxTypeOPUS = 0x4f707573 // 'Opus'
+    SrsMp4BoxTypeDOPS = 0x644f7073 // 'dOps'
+    SrsMp4BoxTypeAC3  = 0x61632d33 // 'ac-3'
+    SrsMp4BoxTypeEC3  = 0x65632d33 // 'ec-3'
+    SrsMp4BoxTypeDAC3 = 0x64616333 // 'dac3'
+    SrsMp4BoxTypeDEC3 = 0x64656333 // 'dec3'
+    SrsMp4BoxTypeFLAC = 0x664c6143 // 'fLaC'
+    SrsMp4BoxTypeDFLA = 0x64664c61 // 'dfLa'
+    SrsMp4BoxTypeIPCM = 0x6970636d // 'ipcm'
+    SrsMp4BoxTypeFPCM = 0x6670636d // 'fpcm'
+    SrsMp4BoxTypeLPCM = 0x6c70636d // 'lpcm'
+    SrsMp4BoxTypeTWOS = 0x74776f73 // 'twos'
+    SrsMp4BoxTypeSOWT = 0x736f7774 // 'sowt'
+    SrsMp4BoxTypePCMC = 0x70636d43 // 'pcmC'
     SrsMp4BoxTypeUDTA = 0x75647461 // 'udta'
 
     SrsMp4BoxBrandForbidden = 0x00
@@ -45,6 +91,7 @@ const (
     SrsMp4BoxBrandISO2 = 0x69736f32 // 'iso2'
     SrsMp4BoxBrandAVC1 = 0x61766331 // 'avc1'
     SrsMp4BoxBrandMP41 = 0x6d703431 // 'mp41'
+    SrsMp4BoxBrandQT = 0x71742020 // 'qt  '
 
     // The type of track, maybe combine of types.
     SrsMp4TrackTypeForbidden = 0x00
@@ -52,6 +99,14 @@ const (
     SrsMp4TrackTypeVideo = 0x02
 )
 
+// The order to iterate the samples of all tracks, see Mp4SampleIterator.
+const (
+    // By the offset in file.
+    SrsMp4SampleOrderFile = 0x00
+    // By the dts in seconds, the timescale of tracks maybe different.
+    SrsMp4SampleOrderDts = 0x01
+)
+
 /**
  * The video codec id.
  * @doc video_file_format_spec_v10_1.pdf, page78, E.4.3.1 VIDEODATA
@@ -80,6 +135,12 @@ const (
     SrsVideoCodecIdOn2VP6WithAlphaChannel = 5
     SrsVideoCodecIdScreenVideoVersion2 = 6
     SrsVideoCodecIdAVC = 7
+    // See https://github.com/veovera/enhanced-rtmp
+    SrsVideoCodecIdHEVC = 12
+    SrsVideoCodecIdAV1 = 13
+    // Not in FLV, for mp4 only.
+    SrsVideoCodecIdVP9 = 64
+    SrsVideoCodecIdVP8 = 65
 )
 
 /**
@@ -127,6 +188,13 @@ const (
     SrsAudioCodecIdSpeex = 11
     SrsAudioCodecIdReservedMP3_8kHz = 14
     SrsAudioCodecIdReservedDeviceSpecificSound = 15
+    // See https://github.com/veovera/enhanced-rtmp
+    SrsAudioCodecIdOpus = 13
+    // Not in FLV, for mp4 only.
+    SrsAudioCodecIdAC3 = 64
+    SrsAudioCodecIdEAC3 = 65
+    SrsAudioCodecIdFLAC = 66
+    SrsAudioCodecIdLinearPCMBigEndian = 67
 )
 
 /**
@@ -246,6 +314,23 @@ const (
     SrsAvcNaluTypeCodedSliceExt = 20
 )
 
+/**
+ * Table 7-1 - NAL unit type codes and NAL unit type classes
+ * T-REC-H.265-201304.pdf, page 60.
+ */
+const (
+    // Video parameter set video_parameter_set_rbsp( )
+    SrsHevcNaluTypeVPS = 32
+    // Sequence parameter set seq_parameter_set_rbsp( )
+    SrsHevcNaluTypeSPS = 33
+    // Picture parameter set pic_parameter_set_rbsp( )
+    SrsHevcNaluTypePPS = 34
+    // Supplemental enhancement information prefix_sei_rbsp( )
+    SrsHevcNaluTypePrefixSEI = 39
+    // Supplemental enhancement information suffix_sei_rbsp( )
+    SrsHevcNaluTypeSuffixSEI = 40
+)
+
 /**
  * the aac profile, for ADTS(HLS/TS)
  * @see https://github.com/ossrs/srs/issues/310
@@ -259,6 +344,43 @@ const (
     SrsAacProfileSSR = 2
 )
 
+/**
+ * the aac object type, for the AudioSpecificConfig.
+ * @see 1.5.1.1 Audio Object type definition, ISO_IEC_14496-3-AAC-2001.pdf, page 23
+ */
+const (
+    SrsAacObjectTypeReserved = 0
+
+    SrsAacObjectTypeAacMain = 1
+    SrsAacObjectTypeAacLC = 2
+    SrsAacObjectTypeAacSSR = 3
+    SrsAacObjectTypeAacLTP = 4
+    // The HE-AAC, AAC-LC with SBR.
+    SrsAacObjectTypeAacHE = 5
+    // The HE-AACv2, AAC-LC with SBR and PS.
+    SrsAacObjectTypeAacHEV2 = 29
+
+    // The escape value, the object type is 32 plus the following 6 bits.
+    SrsAacObjectTypeEscape = 31
+)
+
+/**
+ * the profile for avc/h.264.
+ * @see Annex A Profiles and levels, ISO_IEC_14496-10-AVC-2003.pdf, page 205.
+ */
+const (
+    SrsAvcProfileReserved = 0
+
+    SrsAvcProfileBaseline = 66
+    SrsAvcProfileMain = 77
+    SrsAvcProfileExtended = 88
+    SrsAvcProfileHigh = 100
+    SrsAvcProfileHigh10 = 110
+    SrsAvcProfileHigh422 = 122
+    SrsAvcProfileHigh444 = 144
+    SrsAvcProfileHigh444Predictive = 244
+)
+
 /**
  * the level for avc/h.264.
  * @see Annex A Profiles and levels, ISO_IEC_14496-10-AVC-2003.pdf, page 207.
@@ -280,6 +402,10 @@ const (
     SrsAvcLevel_41 = 41
     SrsAvcLevel_5 = 50
     SrsAvcLevel_51 = 51
+    SrsAvcLevel_52 = 52
+    SrsAvcLevel_6 = 60
+    SrsAvcLevel_61 = 61
+    SrsAvcLevel_62 = 62
 )
 
 /**
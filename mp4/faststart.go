@@ -0,0 +1,179 @@
+package mp4
+
+import (
+    "fmt"
+    ol "github.com/ossrs/go-oryx-lib/logger"
+)
+
+// Relocate the moov ahead of the first mdat, so the player starts without downloading the whole
+// file, and patch the chunk offsets of all tracks for the shifted mdat. The stco is upgraded to co64
+// when the shifted offsets overflow 32 bits.
+// @remark Use Encode to write the file, the payload of mdat is copied from the source file, so the
+// StartPos of boxes is kept as the position in the source file.
+func (v *File) Faststart() (err error) {
+    var moov *Mp4MovieBox
+    if moov, err = v.Moov(); err != nil {
+        return
+    }
+
+    first, index := -1, -1
+    for i, box := range v.Boxes {
+        if box == Box(moov) {
+            index = i
+        }
+        if _, ok := box.(*Mp4MediaDataBox); ok && first < 0 {
+            first = i
+        }
+    }
+    if first < 0 {
+        return fmt.Errorf("no mdat in file")
+    }
+    if index < first {
+        ol.T(nil, "moov is already ahead of mdat")
+        return
+    }
+
+    boxes := make([]Box, 0, len(v.Boxes))
+    boxes = append(boxes, v.Boxes[:first]...)
+    boxes = append(boxes, moov)
+    for i := first; i < len(v.Boxes); i++ {
+        if i != index {
+            boxes = append(boxes, v.Boxes[i])
+        }
+    }
+
+    // The ranges of boxes in the source file, before the size of moov is changed.
+    ranges := make([]boxRange, 0, len(v.Boxes))
+    for _, box := range v.Boxes {
+        start := uint64(box.Basic().StartPos)
+        ranges = append(ranges, boxRange{box: box, start: start, end: start + box.Basic().Size()})
+    }
+
+    // The moov to the end of file is not the last one any more.
+    if moov.SmallSize == SRS_MP4_EOF_SIZE {
+        moov.SmallSize = uint32(moov.NbHeader())
+    }
+
+    var tables []*chunkOffsetTable
+    for _, trak := range moov.Tracks() {
+        var table *chunkOffsetTable
+        if table, err = newChunkOffsetTable(trak); err != nil {
+            return
+        }
+        tables = append(tables, table)
+        trak.samples = nil
+    }
+
+    // The moov grows when stco is upgraded to co64, which shifts the mdat again.
+    for {
+        UpdateSize(moov)
+
+        positions := make(map[Box]uint64)
+        var pos uint64
+        for _, box := range boxes {
+            positions[box] = pos
+            pos += box.Basic().Size()
+        }
+
+        var upgraded bool
+        for _, table := range tables {
+            offsets := make([]uint64, 0, len(table.offsets))
+            for _, offset := range table.offsets {
+                r := boxAt(ranges, offset)
+                if r == nil {
+                    return fmt.Errorf("chunk offset %v out of file", offset)
+                }
+                offsets = append(offsets, offset - r.start + positions[r.box])
+            }
+            if table.patch(offsets) {
+                upgraded = true
+            }
+        }
+
+        if !upgraded {
+            break
+        }
+    }
+
+    v.Boxes = boxes
+    ol.T(nil, fmt.Sprintf("relocate moov ahead of mdat, tracks=%v, moov size=%v", len(tables), moov.Size()))
+    return
+}
+
+// The range [start, end) of top-level box in the source file.
+type boxRange struct {
+    box Box
+    start uint64
+    end uint64
+}
+
+// Get the range of top-level box which contains the offset in the source file.
+func boxAt(ranges []boxRange, offset uint64) *boxRange {
+    for i := range ranges {
+        if offset >= ranges[i].start && offset < ranges[i].end {
+            return &ranges[i]
+        }
+    }
+    return nil
+}
+
+// The chunk offsets of track, in the stco or co64 of stbl.
+type chunkOffsetTable struct {
+    stbl *Mp4SampleTableBox
+    // The index of stco or co64 in stbl.
+    index int
+    // The chunk offsets in the source file.
+    offsets []uint64
+}
+
+func newChunkOffsetTable(trak *Mp4TrackBox) (v *chunkOffsetTable, err error) {
+    // The chunk offsets in the other file are not shifted.
+    if locations := trak.ExternalData(); len(locations) > 0 {
+        return nil, fmt.Errorf("track references external data %v", locations)
+    }
+
+    v = &chunkOffsetTable{}
+    if v.stbl, err = trak.Stbl(); err != nil {
+        return
+    }
+
+    for i, box := range v.stbl.Boxes {
+        if stco, ok := box.(ChunkOffsetBox); ok {
+            for j := 0; j < stco.NbChunks(); j++ {
+                v.offsets = append(v.offsets, stco.ChunkOffset(j))
+            }
+            v.index = i
+            return
+        }
+    }
+    return nil, fmt.Errorf("can't find stco or co64 in stbl")
+}
+
+// Update the chunk offsets, upgrade the stco to co64 when overflow.
+// @return Whether upgraded, the size of stbl is changed.
+func (v *chunkOffsetTable) patch(offsets []uint64) (upgraded bool) {
+    if co64, ok := v.stbl.Boxes[v.index].(*Mp4ChunkLargeOffsetBox); ok {
+        co64.Entries = offsets
+        return
+    }
+
+    stco := v.stbl.Boxes[v.index].(*Mp4ChunkOffsetBox)
+    entries := make([]uint32, 0, len(offsets))
+    for _, offset := range offsets {
+        if offset > 0xffffffff {
+            co64 := NewMp4ChunkLargeOffsetBox()
+            co64.Version, co64.Flags = stco.Version, stco.Flags
+            // Not to the end of file, the size is updated by UpdateSize.
+            co64.SmallSize = uint32(NbBytes(co64))
+            co64.StartPos = stco.StartPos
+            co64.Entries = offsets
+            v.stbl.Boxes[v.index] = co64
+
+            ol.T(nil, fmt.Sprintf("upgrade stco to co64 for offset %v", offset))
+            return true
+        }
+        entries = append(entries, uint32(offset))
+    }
+    stco.Entries = entries
+    return
+}
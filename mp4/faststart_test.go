@@ -0,0 +1,240 @@
+package mp4
+
+import (
+    "bytes"
+    "encoding/binary"
+    "io"
+    "testing"
+)
+
+// Check the samples of all tracks are the same as the test track, which are read from r by the chunk
+// offsets in f.
+func checkSamples(t *testing.T, f *File, r io.ReaderAt, tracks ...*testTrack) {
+    t.Helper()
+    moov, err := f.Moov()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(moov.Tracks()) != len(tracks) {
+        t.Fatalf("tracks %v, expect %v", len(moov.Tracks()), len(tracks))
+    }
+    for i, trak := range moov.Tracks() {
+        for j, expect := range tracks[i].samples {
+            if sample, err := trak.ReadSample(r, j); err != nil || !bytes.Equal(sample, expect) {
+                t.Errorf("track %v sample %v is %v, expect %v, err is %v", tracks[i].id, j, sample, expect, err)
+            }
+        }
+    }
+}
+
+// Faststart the file, return the encoded file and the parsed one.
+func faststart(t *testing.T, b []byte) ([]byte, *File) {
+    t.Helper()
+    f := parseFile(t, b)
+    if err := f.Faststart(); err != nil {
+        t.Fatal(err)
+    }
+    var w bytes.Buffer
+    if err := f.Encode(&w, bytes.NewReader(b)); err != nil {
+        t.Fatal(err)
+    }
+    return w.Bytes(), parseFile(t, w.Bytes())
+}
+
+func topTypes(f *File) (types []string) {
+    for _, box := range f.Boxes {
+        types = append(types, FourCC(box.Basic().BoxType))
+    }
+    return
+}
+
+func TestFaststart(t *testing.T) {
+    video, audio := newAvTracks()
+    b, f := faststart(t, buildAvFile(true))
+
+    // The file is the same as the one built with moov ahead of mdat.
+    if !bytes.Equal(b, buildAvFile(false)) {
+        t.Errorf("faststart %v bytes, expect %v, boxes %v", len(b), len(buildAvFile(false)), topTypes(f))
+    }
+    checkSamples(t, f, bytes.NewReader(b), video, audio)
+
+    // The file of moov ahead of mdat is not changed.
+    if b, _ := faststart(t, buildAvFile(false)); !bytes.Equal(b, buildAvFile(false)) {
+        t.Error("the file of moov ahead is changed")
+    }
+}
+
+func TestFaststartSizeToEndOfFile(t *testing.T) {
+    video, audio := newAvTracks()
+    src := buildAvFile(true)
+    binary.BigEndian.PutUint32(src[parseFile(t, src).Boxes[2].Basic().StartPos:], SRS_MP4_EOF_SIZE)
+
+    // The moov is not the last box, so the size is written.
+    b, f := faststart(t, src)
+    if !bytes.Equal(b, buildAvFile(false)) {
+        t.Errorf("faststart %v bytes, expect %v, boxes %v", len(b), len(buildAvFile(false)), topTypes(f))
+    }
+    checkSamples(t, f, bytes.NewReader(b), video, audio)
+}
+
+func TestFaststartBoxAfterMoov(t *testing.T) {
+    // The file of ftyp, mdat of video, moov, free and mdat of audio.
+    video, audio := newAvTracks()
+    ftyp := box("ftyp", []byte("isom"), be(uint32(512)), []byte("isomiso2avc1mp41"))
+    var vp, ap []byte
+    for _, sample := range video.samples {
+        vp = append(vp, sample...)
+    }
+    for _, sample := range audio.samples {
+        ap = append(ap, sample...)
+    }
+    mdat, free := box("mdat", vp), box("free", make([]byte, 16))
+
+    start := uint64(len(ftyp) + len(mdat))
+    size := uint64(len(moovBox([]*testTrack{video, audio}, [][]uint64{video.offsetsAt(0), audio.offsetsAt(0)})))
+    moov := moovBox([]*testTrack{video, audio}, [][]uint64{
+        video.offsetsAt(uint64(len(ftyp)) + 8),
+        audio.offsetsAt(start + size + uint64(len(free)) + 8),
+    })
+    src := bytes.Join([][]byte{ftyp, mdat, moov, free, box("mdat", ap)}, nil)
+    checkSamples(t, parseFile(t, src), bytes.NewReader(src), video, audio)
+
+    b, f := faststart(t, src)
+    if types := topTypes(f); len(types) != 5 || types[1] != "moov" || types[2] != "mdat" || types[3] != "free" || types[4] != "mdat" {
+        t.Errorf("boxes %v", types)
+    }
+    if len(b) != len(src) {
+        t.Errorf("faststart %v bytes, expect %v", len(b), len(src))
+    }
+    checkSamples(t, f, bytes.NewReader(b), video, audio)
+}
+
+// The file of zeros, except the segments of data, whose mdat is never allocated.
+type sparseFile struct {
+    size int64
+    segments map[int64][]byte
+}
+
+func (v *sparseFile) ReadAt(p []byte, off int64) (n int, err error) {
+    if off >= v.size {
+        return 0, io.EOF
+    }
+    if n = len(p); off + int64(n) > v.size {
+        n, err = int(v.size - off), io.EOF
+    }
+    for i := 0; i < n; i++ {
+        p[i] = 0
+    }
+    for pos, data := range v.segments {
+        for i := range data {
+            if at := pos + int64(i) - off; at >= 0 && at < int64(n) {
+                p[at] = data[i]
+            }
+        }
+    }
+    return
+}
+
+// The writer which keeps the bytes in ranges only, and discards others.
+type rangeWriter struct {
+    pos uint64
+    ranges [][2]uint64
+    data [][]byte
+}
+
+func (v *rangeWriter) Write(p []byte) (n int, err error) {
+    for i, r := range v.ranges {
+        start, end := r[0], r[1]
+        if start < v.pos {
+            start = v.pos
+        }
+        if end > v.pos + uint64(len(p)) {
+            end = v.pos + uint64(len(p))
+        }
+        if start < end {
+            copy(v.data[i][start - r[0]:], p[start - v.pos:end - v.pos])
+        }
+    }
+    v.pos += uint64(len(p))
+    return len(p), nil
+}
+
+func (v *rangeWriter) ReadAt(p []byte, off int64) (n int, err error) {
+    for i, r := range v.ranges {
+        if uint64(off) >= r[0] && uint64(off) + uint64(len(p)) <= r[1] {
+            return copy(p, v.data[i][uint64(off) - r[0]:]), nil
+        }
+    }
+    return 0, io.EOF
+}
+
+func TestFaststartUpgradeCo64(t *testing.T) {
+    if testing.Short() {
+        t.Skip("encode the file of 4GB")
+    }
+
+    // The chunks of video are at the end of a mdat of 4GB, whose offsets overflow 32 bits when shifted by moov.
+    video, audio := newAvTracks()
+    video.samplesPerChunk = 1
+    // The audio is in the mdat after moov, whose offsets are in co64.
+    audio.co64 = true
+
+    ftyp := box("ftyp", []byte("isom"), be(uint32(512)), []byte("isomiso2avc1mp41"))
+    var vp, ap []byte
+    for _, sample := range video.samples {
+        vp = append(vp, sample...)
+    }
+    for _, sample := range audio.samples {
+        ap = append(ap, sample...)
+    }
+
+    vpos := uint64(0xffffff00)
+    start := vpos + uint64(len(vp))
+    mdat := be(uint32(1), []byte("mdat"), start - uint64(len(ftyp)))
+    size := uint64(len(moovBox([]*testTrack{video, audio}, [][]uint64{video.offsetsAt(0), audio.offsetsAt(0)})))
+    moov := moovBox([]*testTrack{video, audio}, [][]uint64{video.offsetsAt(vpos), audio.offsetsAt(start + size + 8)})
+    // The moov grows 40 bytes by the stco of 10 chunks upgraded to co64, so the payload of the audio
+    // mdat after moov is in the range of the grown moov.
+    tail := append(moov, box("mdat", ap)...)
+
+    src := &sparseFile{size: int64(start) + int64(len(tail)), segments: map[int64][]byte{
+        0: append(ftyp, mdat...),
+        int64(vpos): vp,
+        int64(start): tail,
+    }}
+    f, err := Parse(io.NewSectionReader(src, 0, src.size))
+    if err != nil {
+        t.Fatal(err)
+    }
+    checkSamples(t, f, src, video, audio)
+
+    if err = f.Faststart(); err != nil {
+        t.Fatal(err)
+    }
+    co64, err := f.Find("moov/trak/mdia/minf/stbl/co64")
+    if err != nil || co64.(*Mp4ChunkLargeOffsetBox).Entries[0] <= 0xffffffff {
+        t.Fatalf("the stco of video is not upgraded, box %+v, err is %v", co64, err)
+    }
+
+    // Keep the bytes of samples at the patched offsets only.
+    w := &rangeWriter{}
+    moovs, _ := f.Moov()
+    for _, trak := range moovs.Tracks() {
+        samples, err := trak.Samples()
+        if err != nil {
+            t.Fatal(err)
+        }
+        for _, sample := range samples {
+            w.ranges = append(w.ranges, [2]uint64{sample.Offset, sample.Offset + uint64(sample.Size)})
+            w.data = append(w.data, make([]byte, sample.Size))
+        }
+    }
+    if err = f.Encode(w, src); err != nil {
+        t.Fatal(err)
+    }
+    // The file grows by the upgraded stco, 4 bytes for each chunk.
+    if expect := uint64(src.size) + uint64(len(video.chunks())) * 4; w.pos != expect {
+        t.Errorf("faststart %v bytes, expect %v", w.pos, expect)
+    }
+    checkSamples(t, f, w, video, audio)
+}
@@ -0,0 +1,303 @@
+// Package mp4 decodes the box structure of ISO base media (mp4) files.
+package mp4
+
+import (
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "io"
+    "strconv"
+    "strings"
+    ol "github.com/ossrs/go-oryx-lib/logger"
+)
+
+// The decoded mp4 file, which is a sequence of top-level boxes.
+type File struct {
+    Boxes []Box
+}
+
+func NewFile() *File {
+    v := &File{
+        Boxes: []Box{},
+    }
+    return v
+}
+
+// Parse decodes all top-level boxes, and their contained boxes, from r until EOF.
+// The payload of mdat and unknown boxes is skipped by seeking when r is an io.ReadSeeker,
+// for example, an os.File, or discarded with a bounded buffer for plain readers.
+// The last box of size 0 extends to the end of file, which is resolved by the size of file when r
+// is an io.Seeker, or by reading to EOF.
+// The truncated file, whose box overflows the end of file, fails with io.ErrUnexpectedEOF.
+func Parse(r io.Reader) (f *File, err error) {
+    f = NewFile()
+    size := fileSize(r)
+    var pos int
+    for {
+        mb := NewMp4Box()
+        var box Box
+        if box, err = mb.discovery(r); err != nil {
+            if err == io.EOF {
+                return f, nil
+            }
+            ol.E(nil, fmt.Sprintf("discovery box failed, err is %v", err))
+            return
+        }
+
+        b := box.Basic()
+        b.StartPos = pos
+
+        // The last box, extends to the end of file.
+        if b.SmallSize == SRS_MP4_EOF_SIZE && size >= 0 {
+            if b.LargeSize = uint64(size) - uint64(pos); b.LargeSize < b.UsedSize {
+                return nil, fmt.Errorf("invalid box at %v to the end of file %v", pos, size)
+            }
+        } else if size >= 0 && b.Size() > uint64(size - int64(pos)) {
+            // The seek never fails beyond the end of file, so the truncated box must be checked.
+            ol.E(nil, fmt.Sprintf("box %v size %v at %v overflow the file %v", FourCC(b.BoxType), b.Size(), pos, size))
+            return nil, io.ErrUnexpectedEOF
+        } else if b.SmallSize == SRS_MP4_EOF_SIZE && isPayload(box) {
+            var n int64
+            if n, err = io.Copy(io.Discard, r); err != nil {
+                ol.E(nil, fmt.Sprintf("skip box to the end of file failed, err is %v", err))
+                return
+            }
+            b.LargeSize = b.UsedSize + uint64(n)
+            skipPayload(box)
+
+            f.Boxes = append(f.Boxes, box)
+            return f, nil
+        } else if b.SmallSize == SRS_MP4_EOF_SIZE {
+            var data []uint8
+            if data, err = io.ReadAll(r); err != nil {
+                ol.E(nil, fmt.Sprintf("read box to the end of file failed, err is %v", err))
+                return
+            }
+            b.LargeSize = b.UsedSize + uint64(len(data))
+            r = bytes.NewReader(data)
+        }
+
+        if err = box.DecodeHeader(r); err != nil {
+            ol.E(nil, fmt.Sprintf("mp4 decode contained box header failed, err is %v", err))
+            return
+        }
+
+        if err = box.Basic().DecodeBoxes(r); err != nil {
+            ol.E(nil, fmt.Sprintf("mp4 decode contained box boxes failed, err is %v", err))
+            return
+        }
+
+        f.Boxes = append(f.Boxes, box)
+        pos += int(box.Basic().Size())
+    }
+}
+
+// ParseAt decodes the top-level boxes of a file of size bytes, which is accessed by offset, for
+// example, a remote file by HttpReader. The payload of mdat and free boxes is never read, so only the
+// box headers and the metadata, for example, the moov at the head or the tail of file, are fetched.
+func ParseAt(r io.ReaderAt, size int64) (f *File, err error) {
+    f = NewFile()
+    var pos int64
+    for pos < size {
+        // The size, type, largesize and usertype, not to read the payload of mdat.
+        var header []uint8
+        if header, err = readBoxHeader(r, pos, size); err != nil {
+            ol.E(nil, fmt.Sprintf("read box header at %v failed, err is %v", pos, err))
+            return nil, err
+        }
+
+        mb := NewMp4Box()
+        var box Box
+        if box, err = mb.discovery(bytes.NewReader(header)); err != nil {
+            ol.E(nil, fmt.Sprintf("discovery box at %v failed, err is %v", pos, err))
+            return
+        }
+
+        b := box.Basic()
+        b.StartPos = int(pos)
+
+        // The last box, extends to the end of file.
+        if b.SmallSize == SRS_MP4_EOF_SIZE {
+            if b.LargeSize = uint64(size - pos); b.LargeSize < b.UsedSize {
+                return nil, fmt.Errorf("invalid box at %v to the end of file %v", pos, size)
+            }
+        }
+        if b.Size() > uint64(size - pos) {
+            return nil, fmt.Errorf("box size %v at %v overflow the file %v", b.Size(), pos, size)
+        }
+
+        if isPayload(box) {
+            skipPayload(box)
+        } else {
+            data := make([]uint8, b.left())
+            var n int
+            if n, err = r.ReadAt(data, pos + int64(b.UsedSize)); n < len(data) {
+                if err == nil || err == io.EOF {
+                    err = io.ErrUnexpectedEOF
+                }
+                ol.E(nil, fmt.Sprintf("read box %v at %v failed, err is %v", FourCC(b.BoxType), pos, err))
+                return nil, err
+            }
+            err = nil
+
+            body := bytes.NewReader(data)
+            if err = box.DecodeHeader(body); err != nil {
+                ol.E(nil, fmt.Sprintf("mp4 decode contained box header failed, err is %v", err))
+                return
+            }
+
+            if err = box.Basic().DecodeBoxes(body); err != nil {
+                ol.E(nil, fmt.Sprintf("mp4 decode contained box boxes failed, err is %v", err))
+                return
+            }
+        }
+
+        f.Boxes = append(f.Boxes, box)
+        pos += int64(b.Size())
+    }
+    return f, nil
+}
+
+// Read the header of box at pos, the size and type, then the largesize and usertype when present,
+// so no byte after the header is read.
+func readBoxHeader(r io.ReaderAt, pos, size int64) (header []uint8, err error) {
+    read := func(n int) (err error) {
+        if pos + int64(len(header) + n) > size {
+            return io.ErrUnexpectedEOF
+        }
+        data := make([]uint8, n)
+        var nn int
+        if nn, err = r.ReadAt(data, pos + int64(len(header))); nn == n {
+            err = nil
+        } else if err == nil || err == io.EOF {
+            err = io.ErrUnexpectedEOF
+        }
+        header = append(header, data[:nn]...)
+        return
+    }
+
+    if err = read(8); err != nil {
+        return
+    }
+    if binary.BigEndian.Uint32(header) == SRS_MP4_USE_LARGE_SIZE {
+        if err = read(8); err != nil {
+            return
+        }
+    }
+    if binary.BigEndian.Uint32(header[4:]) == SrsMp4BoxTypeUUID {
+        if err = read(16); err != nil {
+            return
+        }
+    }
+    return
+}
+
+// Get the size of file by seeking to the end, -1 if unknown.
+func fileSize(r io.Reader) int64 {
+    rs, ok := r.(io.Seeker)
+    if !ok {
+        return -1
+    }
+
+    var cur, end int64
+    var err error
+    if cur, err = rs.Seek(0, io.SeekCurrent); err != nil {
+        return -1
+    }
+    if end, err = rs.Seek(0, io.SeekEnd); err != nil {
+        return -1
+    }
+    if _, err = rs.Seek(cur, io.SeekStart); err != nil {
+        return -1
+    }
+    return end
+}
+
+// Whether the box is mdat or free, whose payload is skipped.
+func isPayload(box Box) bool {
+    switch box.(type) {
+    case *Mp4MediaDataBox, *Mp4FreeSpaceBox:
+        return true
+    }
+    return false
+}
+
+// Set the payload of mdat or free box, which is not read.
+func skipPayload(box Box) {
+    b := box.Basic()
+    switch box := box.(type) {
+    case *Mp4MediaDataBox:
+        box.DataOffset = b.StartPos + int(b.UsedSize)
+        box.NbData = int(b.left())
+    case *Mp4FreeSpaceBox:
+        box.DataOffset = b.StartPos + int(b.UsedSize)
+        box.needSkip = int(b.left())
+    }
+}
+
+// Get the top-level box of specific type.
+// @return The first matched box.
+func (v *File) Get(bt uint32) (Box, error) {
+    for _, box := range v.Boxes {
+        if box.Basic().BoxType == bt {
+            return box, nil
+        }
+    }
+    return nil, fmt.Errorf("can't find bt:%v in file", bt)
+}
+
+func (v *File) Ftyp() (*Mp4FileTypeBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeFTYP); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4FileTypeBox), nil
+    }
+}
+
+func (v *File) Moov() (*Mp4MovieBox, error) {
+    if box, err := v.Get(SrsMp4BoxTypeMOOV); err != nil {
+        return nil, err
+    } else {
+        return box.(*Mp4MovieBox), nil
+    }
+}
+
+// Get the contained boxes, or the entries for stsd.
+func Contained(box Box) []Box {
+    if stsd, ok := box.(*Mp4SampleDescritionBox); ok {
+        return stsd.Entries
+    }
+    return box.Basic().Boxes
+}
+
+// Find the box by path, for example, "moov/trak[1]/mdia/minf/stbl/stsz", where the index
+// selects among the boxes of the same type, default to the first one.
+func (v *File) Find(path string) (box Box, err error) {
+    boxes := v.Boxes
+    for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+        index := 0
+        if pos := strings.Index(name, "["); pos > 0 && strings.HasSuffix(name, "]") {
+            if index, err = strconv.Atoi(name[pos + 1:len(name) - 1]); err != nil {
+                return nil, fmt.Errorf("invalid index of %v, err is %v", name, err)
+            }
+            name = name[:pos]
+        }
+
+        box = nil
+        for _, b := range boxes {
+            if FourCC(b.Basic().BoxType) != name {
+                continue
+            }
+            if index == 0 {
+                box = b
+                break
+            }
+            index--
+        }
+        if box == nil {
+            return nil, fmt.Errorf("can't find %v of path %v", name, path)
+        }
+        boxes = Contained(box)
+    }
+    return
+}
@@ -0,0 +1,201 @@
+package mp4
+
+import (
+    "bytes"
+    "encoding/binary"
+    "io"
+    "testing"
+)
+
+func TestParse(t *testing.T) {
+    f := parseFile(t, buildAvFile(false))
+
+    var types []string
+    for _, box := range f.Boxes {
+        types = append(types, FourCC(box.Basic().BoxType))
+    }
+    if len(types) != 3 || types[0] != "ftyp" || types[1] != "moov" || types[2] != "mdat" {
+        t.Fatalf("top-level boxes %v", types)
+    }
+
+    ftyp, err := f.Ftyp()
+    if err != nil || FourCC(ftyp.MajorBrand) != "isom" || ftyp.MinorVersion != 512 || len(ftyp.CompatibleBrands) != 4 {
+        t.Fatalf("ftyp %+v, err is %v", ftyp, err)
+    }
+
+    moov, err := f.Moov()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if moov.NbVideoTracks() != 1 || moov.NbSoundTracks() != 1 || len(moov.Tracks()) != 2 {
+        t.Fatalf("video=%v, sound=%v, tracks=%v", moov.NbVideoTracks(), moov.NbSoundTracks(), len(moov.Tracks()))
+    }
+    if mvhd, err := moov.Mvhd(); err != nil || mvhd.TimeScale != 1000 || mvhd.NextTrackId != 3 {
+        t.Fatalf("mvhd %+v, err is %v", mvhd, err)
+    }
+}
+
+func TestFind(t *testing.T) {
+    f := parseFile(t, buildAvFile(false))
+
+    cases := []struct {
+        path string
+        bt uint32
+        ok bool
+    }{
+        {"moov", SrsMp4BoxTypeMOOV, true},
+        {"/moov/mvhd/", SrsMp4BoxTypeMVHD, true},
+        {"moov/trak/mdia/hdlr", SrsMp4BoxTypeHDLR, true},
+        {"moov/trak[1]/mdia/minf/stbl/stsd/mp4a/esds", SrsMp4BoxTypeESDS, true},
+        {"moov/trak[2]", 0, false},
+        {"moov/trak[x]", 0, false},
+        {"moov/udta", 0, false},
+    }
+    for _, c := range cases {
+        box, err := f.Find(c.path)
+        if !c.ok {
+            if err == nil {
+                t.Errorf("find %v should fail", c.path)
+            }
+            continue
+        }
+        if err != nil || box.Basic().BoxType != c.bt {
+            t.Errorf("find %v got %v, err is %v", c.path, box, err)
+        }
+    }
+
+    hdlr, err := f.Find("moov/trak[1]/mdia/hdlr")
+    if err != nil || hdlr.(*Mp4HandlerReferenceBox).HandlerType != SrsMp4HandlerTypeSOUN {
+        t.Fatalf("the second track is not audio, err is %v", err)
+    }
+}
+
+// Parse by the seeker, and by the plain reader.
+func parseBoth(t *testing.T, b []byte, fn func(name string, f *File, err error)) {
+    t.Helper()
+    f, err := Parse(bytes.NewReader(b))
+    fn("seeker", f, err)
+    f, err = Parse(&countingReader{r: bytes.NewReader(b)})
+    fn("reader", f, err)
+}
+
+var testMdat = bytes.Repeat([]byte{0xab}, 100)
+
+// Append the mdat of testMdat, with the header of size and type.
+func withMdat(header []byte) []byte {
+    return bytes.Join([][]byte{buildAvFile(false), header, testMdat}, nil)
+}
+
+func TestLargeSize(t *testing.T) {
+    b := withMdat(be(uint32(1), []byte("mdat"), uint64(16 + len(testMdat))))
+    parseBoth(t, b, func(name string, f *File, err error) {
+        if err != nil {
+            t.Fatalf("%v: %v", name, err)
+        }
+        mdat := f.Boxes[len(f.Boxes) - 1].(*Mp4MediaDataBox)
+        if mdat.SmallSize != SRS_MP4_USE_LARGE_SIZE || mdat.Size() != uint64(16 + len(testMdat)) || mdat.NbHeader() != 16 + len(testMdat) {
+            t.Errorf("%v: mdat %+v", name, mdat.Mp4Box)
+        }
+        if mdat.DataOffset != len(b) - len(testMdat) || mdat.NbData != len(testMdat) {
+            t.Errorf("%v: mdat payload at %v, size %v", name, mdat.DataOffset, mdat.NbData)
+        }
+
+        var w bytes.Buffer
+        if err := f.Encode(&w, bytes.NewReader(b)); err != nil || !bytes.Equal(w.Bytes(), b) {
+            t.Errorf("%v: the largesize is not kept, err is %v", name, err)
+        }
+    })
+}
+
+func TestSizeToEndOfFile(t *testing.T) {
+    b := withMdat(be(uint32(0), []byte("mdat")))
+    parseBoth(t, b, func(name string, f *File, err error) {
+        if err != nil {
+            t.Fatalf("%v: %v", name, err)
+        }
+        mdat := f.Boxes[len(f.Boxes) - 1].(*Mp4MediaDataBox)
+        if mdat.SmallSize != SRS_MP4_EOF_SIZE || mdat.Size() != uint64(8 + len(testMdat)) || mdat.NbData != len(testMdat) {
+            t.Errorf("%v: mdat %+v, payload %v", name, mdat.Mp4Box, mdat.NbData)
+        }
+
+        var w bytes.Buffer
+        if err := f.Encode(&w, bytes.NewReader(b)); err != nil || !bytes.Equal(w.Bytes(), b) {
+            t.Errorf("%v: the size 0 is not kept, err is %v", name, err)
+        }
+    })
+
+    // The moov at the end of file of size 0, whose contained boxes are decoded.
+    b = buildAvFile(true)
+    start := parseFile(t, b).Boxes[2].Basic().StartPos
+    binary.BigEndian.PutUint32(b[start:], 0)
+    parseBoth(t, b, func(name string, f *File, err error) {
+        if err != nil {
+            t.Fatalf("%v: %v", name, err)
+        }
+        moov, err := f.Moov()
+        if err != nil || moov.Size() != uint64(len(b) - start) || len(moov.Tracks()) != 2 {
+            t.Fatalf("%v: moov %+v, err is %v", name, moov, err)
+        }
+        var w bytes.Buffer
+        if err := f.Encode(&w, bytes.NewReader(b)); err != nil || !bytes.Equal(w.Bytes(), b) {
+            t.Errorf("%v: the size 0 is not kept, err is %v", name, err)
+        }
+    })
+}
+
+func TestTruncated(t *testing.T) {
+    full := withMdat(be(uint32(8 + len(testMdat)), []byte("mdat")))
+    cases := []struct {
+        name string
+        b []byte
+    }{
+        // The mdat claims more bytes than the file.
+        {"mdat", full[:len(full) - 10]},
+        // The box claims a huge size.
+        {"huge", withMdat(be(uint32(0x7fffffff), []byte("mdat")))},
+        {"large", withMdat(be(uint32(1), []byte("mdat"), uint64(1) << 40))},
+        {"unknown", withMdat(be(uint32(0x7fffffff), []byte("skip")))},
+        // The moov is truncated.
+        {"moov", buildAvFile(true)[:len(buildAvFile(true)) - 10]},
+    }
+    for _, c := range cases {
+        parseBoth(t, c.b, func(name string, f *File, err error) {
+            if err != io.ErrUnexpectedEOF {
+                t.Errorf("%v of %v: err is %v, expect unexpected EOF", c.name, name, err)
+            }
+        })
+    }
+}
+
+func TestMalformedEsds(t *testing.T) {
+    dcd := func(dsi []byte) []byte {
+        return descriptor(0x04, []byte{0x40, 0x15}, make([]byte, 3), be(uint32(128000), uint32(128000)), dsi)
+    }
+    es := func(dcd []byte) []byte {
+        return descriptor(0x03, be(uint16(2), uint8(0)), dcd, descriptor(0x06, []byte{0x02}))
+    }
+    cases := []struct {
+        name string
+        es []byte
+    }{
+        // The size of 5 bytes overflows the int32 to negative.
+        {"negative size", es(dcd([]byte{0x05, 0xff, 0xff, 0xff, 0xff, 0x7f, 0x11, 0x90}))},
+        // The size of 4 bytes is valid, but larger than the DecoderConfigDescriptor.
+        {"dsi overflow", es(dcd([]byte{0x05, 0x80, 0x80, 0x80, 0x7f, 0x11, 0x90}))},
+        {"dsi huge", es(dcd([]byte{0x05, 0xff, 0xff, 0xff, 0x7f, 0x11, 0x90}))},
+        // The ES_Descriptor is larger than the esds box.
+        {"es overflow", []byte{0x03, 0x80, 0x80, 0x81, 0x00, 0x00, 0x02, 0x00}},
+        // The DecoderConfigDescriptor is larger than the ES_Descriptor.
+        {"dcd overflow", descriptor(0x03, be(uint16(2), uint8(0)), []byte{0x04, 0xff, 0xff, 0xff, 0x7f, 0x40})},
+    }
+    for _, c := range cases {
+        audio := newTestTrack(2, "soun", audioEntry("mp4a", 2, 16, 48000, fullBox("esds", 0, 0, c.es)), 3)
+        b := buildFile(false, audio)
+        if _, err := Parse(bytes.NewReader(b)); err == nil {
+            t.Errorf("%v: parse should fail", c.name)
+        }
+        if _, err := ParseAt(bytes.NewReader(b), int64(len(b))); err == nil {
+            t.Errorf("%v: parse at should fail", c.name)
+        }
+    }
+}
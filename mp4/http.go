@@ -0,0 +1,136 @@
+package mp4
+
+import (
+    "fmt"
+    "io"
+    "net/http"
+    "strconv"
+    "strings"
+    ol "github.com/ossrs/go-oryx-lib/logger"
+)
+
+// The remote mp4 file over http(s), which reads at offset by HTTP Range requests,
+// so only the requested bytes are downloaded.
+type HttpReader struct {
+    client *http.Client
+    url string
+    size int64
+}
+
+// Open the remote file, the size is discovered by a range request of the first byte.
+func NewHttpReader(client *http.Client, url string) (v *HttpReader, err error) {
+    if client == nil {
+        client = http.DefaultClient
+    }
+    v = &HttpReader{
+        client: client,
+        url: url,
+    }
+
+    var res *http.Response
+    if res, v.size, err = v.get(0, 0); err != nil {
+        return nil, err
+    }
+    defer res.Body.Close()
+
+    ol.T(nil, fmt.Sprintf("open remote mp4 %v success, size=%v", url, v.size))
+    return
+}
+
+// Get the size of the remote file.
+func (v *HttpReader) Size() int64 {
+    return v.size
+}
+
+// Request the range of first to last, the Content-Range of response must be the same range, and the
+// size of file is parsed from it.
+func (v *HttpReader) get(first, last int64) (res *http.Response, size int64, err error) {
+    var req *http.Request
+    if req, err = http.NewRequest("GET", v.url, nil); err != nil {
+        return nil, 0, err
+    }
+    req.Header.Set("Range", fmt.Sprintf("bytes=%v-%v", first, last))
+
+    if res, err = v.client.Do(req); err != nil {
+        ol.E(nil, fmt.Sprintf("request %v range %v-%v failed, err is %v", v.url, first, last, err))
+        return nil, 0, err
+    }
+
+    // We never download the whole file, which maybe GBs of mdat.
+    if res.StatusCode != http.StatusPartialContent {
+        res.Body.Close()
+        err = fmt.Errorf("request %v range %v-%v failed, status is %v, range not supported", v.url, first, last, res.Status)
+        return nil, 0, err
+    }
+
+    // The proxy or CDN maybe respond another range, which must not be decoded as the requested one.
+    var rfirst, rlast int64
+    if rfirst, rlast, size, err = parseContentRange(res.Header.Get("Content-Range")); err == nil && (rfirst != first || rlast != last) {
+        err = fmt.Errorf("range %v-%v mismatch, requested %v-%v", rfirst, rlast, first, last)
+    }
+    if err != nil {
+        res.Body.Close()
+        ol.E(nil, fmt.Sprintf("request %v range %v-%v failed, err is %v", v.url, first, last, err))
+        return nil, 0, err
+    }
+    return
+}
+
+// Parse the Content-Range of the 206 response, for example, bytes 0-0/12345.
+func parseContentRange(cr string) (first, last, size int64, err error) {
+    s := strings.TrimPrefix(cr, "bytes ")
+    dash, slash := strings.Index(s, "-"), strings.LastIndex(s, "/")
+    if s == cr || dash < 0 || slash < dash {
+        return 0, 0, 0, fmt.Errorf("invalid content range %v", cr)
+    }
+    if first, err = strconv.ParseInt(s[:dash], 10, 64); err == nil {
+        if last, err = strconv.ParseInt(s[dash + 1:slash], 10, 64); err == nil {
+            size, err = strconv.ParseInt(s[slash + 1:], 10, 64)
+        }
+    }
+    if err != nil || first < 0 || last < first || last >= size {
+        return 0, 0, 0, fmt.Errorf("invalid content range %v", cr)
+    }
+    return
+}
+
+func (v *HttpReader) ReadAt(p []byte, off int64) (n int, err error) {
+    if off < 0 {
+        return 0, fmt.Errorf("invalid offset %v", off)
+    }
+    if off >= v.size {
+        return 0, io.EOF
+    }
+    if len(p) == 0 {
+        return
+    }
+
+    last := off + int64(len(p)) - 1
+    if last >= v.size {
+        last = v.size - 1
+    }
+
+    var res *http.Response
+    var size int64
+    if res, size, err = v.get(off, last); err != nil {
+        return
+    }
+    defer res.Body.Close()
+
+    if size != v.size {
+        err = fmt.Errorf("size of %v changed from %v to %v", v.url, v.size, size)
+        ol.E(nil, fmt.Sprintf("read %v range %v-%v failed, err is %v", v.url, off, last, err))
+        return
+    }
+
+    if n, err = io.ReadFull(res.Body, p[:last - off + 1]); err != nil {
+        ol.E(nil, fmt.Sprintf("read %v range %v-%v failed, err is %v", v.url, off, last, err))
+        return
+    }
+    ol.I(nil, fmt.Sprintf("read %v range %v-%v, %v bytes", v.url, off, last, n))
+
+    if n < len(p) {
+        err = io.EOF
+    }
+    return
+}
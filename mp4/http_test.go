@@ -0,0 +1,179 @@
+package mp4
+
+import (
+    "bytes"
+    "fmt"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "testing"
+    "time"
+)
+
+// The server of the file, which records the requested ranges.
+type rangeServer struct {
+    data []byte
+    // Whether to ignore the Range and respond the whole file by 200.
+    noRange bool
+    // Respond 500 for the request after this number of requests, 0 to disable.
+    failAfter int
+    // Respond the range shifted by this number of bytes, except the first request for the size.
+    shift int64
+
+    lock sync.Mutex
+    ranges [][2]int64
+}
+
+func (v *rangeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    v.lock.Lock()
+    defer v.lock.Unlock()
+
+    if v.noRange {
+        w.Write(v.data)
+        return
+    }
+    if v.failAfter > 0 && len(v.ranges) >= v.failAfter {
+        http.Error(w, "failed", http.StatusInternalServerError)
+        return
+    }
+
+    var first, last int64
+    if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &first, &last); err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    v.ranges = append(v.ranges, [2]int64{first, last})
+    if v.shift != 0 && len(v.ranges) > 1 {
+        r.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", first + v.shift, last + v.shift))
+    }
+    http.ServeContent(w, r, "test.mp4", time.Time{}, bytes.NewReader(v.data))
+}
+
+func TestParseAtHttp(t *testing.T) {
+    data := buildAvFile(true)
+    rs := &rangeServer{data: data}
+    server := httptest.NewServer(rs)
+    defer server.Close()
+
+    r, err := NewHttpReader(server.Client(), server.URL)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if r.Size() != int64(len(data)) {
+        t.Fatalf("size %v, expect %v", r.Size(), len(data))
+    }
+
+    f, err := ParseAt(r, r.Size())
+    if err != nil {
+        t.Fatal(err)
+    }
+    expect := parseFile(t, data)
+    if len(f.Boxes) != len(expect.Boxes) {
+        t.Fatalf("boxes %v, expect %v", len(f.Boxes), len(expect.Boxes))
+    }
+    moov, err := f.Moov()
+    if err != nil || len(moov.Tracks()) != 2 {
+        t.Fatalf("moov at the end of file is not parsed, err is %v", err)
+    }
+
+    // The moov is fetched, but the payload of mdat is never requested.
+    mdat := f.Boxes[1].(*Mp4MediaDataBox)
+    first, last := int64(mdat.DataOffset), int64(mdat.DataOffset + mdat.NbData - 1)
+    for _, rg := range rs.ranges {
+        if rg[0] <= last && rg[1] >= first {
+            t.Errorf("range %v-%v overlaps the mdat payload %v-%v", rg[0], rg[1], first, last)
+        }
+    }
+
+    // The samples read by range are the same as the file.
+    samples, err := moov.Tracks()[1].Samples()
+    if err != nil {
+        t.Fatal(err)
+    }
+    sample, err := ReadSample(r, samples[3])
+    if err != nil || !bytes.Equal(sample, bytes.Repeat([]byte{2, 3}, 4)) {
+        t.Errorf("sample %v, err is %v", sample, err)
+    }
+}
+
+func TestHttpReaderNoRange(t *testing.T) {
+    server := httptest.NewServer(&rangeServer{data: buildAvFile(true), noRange: true})
+    defer server.Close()
+
+    if _, err := NewHttpReader(server.Client(), server.URL); err == nil {
+        t.Fatal("the server without range support should be rejected")
+    }
+}
+
+func TestParseAtHttpFailed(t *testing.T) {
+    // The size and the header of ftyp are fetched, then the server fails.
+    server := httptest.NewServer(&rangeServer{data: buildAvFile(true), failAfter: 2})
+    defer server.Close()
+
+    r, err := NewHttpReader(server.Client(), server.URL)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if f, err := ParseAt(r, r.Size()); err == nil {
+        t.Fatalf("the failed range request is not an error, boxes=%v", len(f.Boxes))
+    }
+}
+
+func TestHttpReaderRangeMismatch(t *testing.T) {
+    // The server responds the range after the requested one, for example, a broken proxy.
+    server := httptest.NewServer(&rangeServer{data: buildAvFile(true), shift: 4})
+    defer server.Close()
+
+    r, err := NewHttpReader(server.Client(), server.URL)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := r.ReadAt(make([]byte, 8), 0); err == nil {
+        t.Error("the shifted range should fail")
+    }
+    if f, err := ParseAt(r, r.Size()); err == nil {
+        t.Errorf("the shifted range is decoded, boxes=%v", len(f.Boxes))
+    }
+}
+
+func TestHttpReaderNegativeOffset(t *testing.T) {
+    rs := &rangeServer{data: buildAvFile(true)}
+    server := httptest.NewServer(rs)
+    defer server.Close()
+
+    r, err := NewHttpReader(server.Client(), server.URL)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if n, err := r.ReadAt(make([]byte, 8), -8); err == nil || n != 0 {
+        t.Errorf("read at -8 got %v bytes, err is %v", n, err)
+    }
+    // Only the request for the size.
+    if len(rs.ranges) != 1 {
+        t.Errorf("requested %v", rs.ranges)
+    }
+}
+
+func TestParseContentRange(t *testing.T) {
+    cases := []struct {
+        cr string
+        ok bool
+        first, last, size int64
+    }{
+        {"bytes 0-0/12345", true, 0, 0, 12345},
+        {"bytes 100-199/200", true, 100, 199, 200},
+        {"bytes */12345", false, 0, 0, 0},
+        {"bytes 0-0/*", false, 0, 0, 0},
+        {"0-0/12345", false, 0, 0, 0},
+        {"bytes 10-5/12345", false, 0, 0, 0},
+        {"bytes 0-200/200", false, 0, 0, 0},
+        {"bytes -1-0/200", false, 0, 0, 0},
+        {"", false, 0, 0, 0},
+    }
+    for _, c := range cases {
+        first, last, size, err := parseContentRange(c.cr)
+        if (err == nil) != c.ok || first != c.first || last != c.last || size != c.size {
+            t.Errorf("%q is %v-%v/%v, err is %v", c.cr, first, last, size, err)
+        }
+    }
+}
@@ -0,0 +1,648 @@
+package mp4
+
+import (
+    "encoding/hex"
+    "encoding/json"
+    "strings"
+)
+
+// The box which exposes its decoded fields in the json output.
+// The keys are snake_case, byte strings are lower-case hex and four character codes are strings.
+type FieldsBox interface {
+    Fields() map[string]interface{}
+}
+
+/**
+ * The json schema of a box, see README.md for the fields of each known box.
+ *      type, the four character code of the box, for example, "moov".
+ *      offset, the position of the box in the file.
+ *      size, the entire size of the box, including the header and contained boxes.
+ *      header_size, the size of the size, type, largesize and usertype.
+ *      version and flags, only for full boxes.
+ *      fields, the decoded fields, only for known boxes.
+ *      boxes, the contained boxes, or the entries of stsd.
+ */
+type BoxJSON struct {
+    Type       string                 `json:"type"`
+    UserType   string                 `json:"user_type,omitempty"`
+    Offset     uint64                 `json:"offset"`
+    Size       uint64                 `json:"size"`
+    HeaderSize int                    `json:"header_size"`
+    Version    *uint8                 `json:"version,omitempty"`
+    Flags      *uint32                `json:"flags,omitempty"`
+    Fields     map[string]interface{} `json:"fields,omitempty"`
+    Boxes      []*BoxJSON             `json:"boxes,omitempty"`
+}
+
+func NewBoxJSON(box Box) *BoxJSON {
+    b := box.Basic()
+    v := &BoxJSON{
+        Type: FourCC(b.BoxType),
+        Offset: uint64(b.StartPos),
+        Size: b.Size(),
+        HeaderSize: b.NbHeader(),
+    }
+
+    if b.BoxType == SrsMp4BoxTypeUUID {
+        v.UserType = hex.EncodeToString(b.UserType[:])
+    }
+
+    if fb, ok := box.(FullBox); ok {
+        version, flags := fb.Full().Version, fb.Full().Flags
+        v.Version, v.Flags = &version, &flags
+    }
+
+    if fb, ok := box.(FieldsBox); ok {
+        v.Fields = fb.Fields()
+    }
+
+    for _, child := range Contained(box) {
+        v.Boxes = append(v.Boxes, NewBoxJSON(child))
+    }
+    return v
+}
+
+/**
+ * The json schema of the summary of a track.
+ *      track_id, handler, the id in tkhd and the handler type in hdlr, for example, "vide".
+ *      codec, the four character code of the sample entry, for example, "avc1".
+ *      codecs, the codecs parameter of RFC 6381, for example, "avc1.64001f".
+ *      timescale, media_duration, sample_count, in mdhd and stsz or stz2.
+ *      duration, start_offset, edits, by the edit list, in the timescale of mdhd, the duration is in the
+ *          presentation timeline after edits, and the start_offset maps the media time to the presentation
+ *          time, for example, -1024 for the priming of aac.
+ *      width, height, only for video, in the sample entry.
+ *      channel_count, sample_rate, only for audio, in the sample entry.
+ *      description, only for aac, by the AudioSpecificConfig, for example, AAC-LC 48 kHz stereo.
+ */
+type TrackJSON struct {
+    TrackId      uint32 `json:"track_id"`
+    Handler      string `json:"handler"`
+    Codec        string `json:"codec,omitempty"`
+    Codecs       string `json:"codecs,omitempty"`
+    TimeScale    uint32 `json:"timescale"`
+    Duration     uint64 `json:"duration"`
+    MediaDuration uint64 `json:"media_duration"`
+    SampleCount  uint32 `json:"sample_count"`
+    StartOffset  int64  `json:"start_offset"`
+    Edits        []*Mp4Edit `json:"edits,omitempty"`
+    Width        uint16 `json:"width,omitempty"`
+    Height       uint16 `json:"height,omitempty"`
+    ChannelCount uint16 `json:"channel_count,omitempty"`
+    SampleRate   uint32 `json:"sample_rate,omitempty"`
+    Atmos        bool   `json:"atmos,omitempty"`
+    BitsPerSample uint16 `json:"bits_per_sample,omitempty"`
+    Endianness   string `json:"endianness,omitempty"`
+    Description  string `json:"description,omitempty"`
+    ExternalData []string `json:"external_data,omitempty"`
+}
+
+func NewTrackJSON(moov *Mp4MovieBox, trak *Mp4TrackBox) *TrackJSON {
+    v := &TrackJSON{}
+    var movieTimeScale uint32
+    if mvhd, err := moov.Mvhd(); err == nil {
+        movieTimeScale = mvhd.TimeScale
+    }
+    if tkhd, err := trak.Tkhd(); err == nil {
+        v.TrackId = tkhd.TrackId
+    }
+    if hdlr, err := trak.Hdlr(); err == nil {
+        v.Handler = FourCC(hdlr.HandlerType)
+    }
+    if mdhd, err := trak.Mdhd(); err == nil {
+        v.TimeScale, v.MediaDuration = mdhd.TimeScale, mdhd.Duration
+    }
+    if stsz, err := trak.Stsz(); err == nil {
+        v.SampleCount = uint32(stsz.NbSamples())
+    }
+    v.Duration = v.MediaDuration
+    if duration, err := trak.PresentationDuration(movieTimeScale); err == nil {
+        v.Duration = duration
+    }
+    if offset, err := trak.StartOffset(movieTimeScale); err == nil {
+        v.StartOffset = offset
+    }
+    if _, err := trak.Elst(); err == nil {
+        v.Edits, _ = trak.Edits(movieTimeScale)
+    }
+    if stsd, err := trak.Stsd(); err == nil && len(stsd.Entries) > 0 {
+        v.Codec = FourCC(stsd.Entries[0].Basic().BoxType)
+    }
+    if codecs, err := trak.Codecs(); err == nil {
+        v.Codecs = codecs
+    }
+    if visual, err := trak.Visual(); err == nil {
+        v.Width, v.Height = visual.Width, visual.Height
+    }
+    if audio, err := trak.Audio(); err == nil {
+        v.ChannelCount, v.SampleRate = uint16(audio.Channels()), audio.SamplingRate()
+        if _, err := audio.Dfla(); err == nil {
+            v.BitsPerSample = uint16(audio.BitsPerSample())
+        }
+        if audio.IsPCM() {
+            v.BitsPerSample, v.Endianness = uint16(audio.BitsPerSample()), endianness(audio.LittleEndian())
+        }
+
+        // The channel count and sample rate of aac entry may be the core, for example, HE-AAC.
+        if aac, err := trak.AacConfig(); err == nil {
+            v.Description = aac.String()
+            if channels := aac.Channels(); channels > 0 {
+                v.ChannelCount, v.SampleRate = uint16(channels), aac.SampleRate()
+            }
+        }
+
+        // The channel count of Dolby audio entry is ignored, see ETSI TS 102 366 F.3 and F.5.
+        if dac3, err := audio.Dac3(); err == nil {
+            v.ChannelCount, v.SampleRate = uint16(dac3.ChannelCount()), dac3.SampleRate()
+        }
+        if dec3, err := audio.Dec3(); err == nil {
+            v.ChannelCount, v.SampleRate, v.Atmos = uint16(dec3.ChannelCount()), dec3.SampleRate(), dec3.Atmos()
+        }
+    }
+    v.ExternalData = trak.ExternalData()
+    return v
+}
+
+// The json schema of the file, which is the tree of top-level boxes, and the summary of tracks.
+type FileJSON struct {
+    Boxes  []*BoxJSON   `json:"boxes"`
+    Tracks []*TrackJSON `json:"tracks,omitempty"`
+    MimeType string     `json:"mime_type,omitempty"`
+}
+
+func NewFileJSON(f *File) *FileJSON {
+    v := &FileJSON{
+        Boxes: []*BoxJSON{},
+    }
+    for _, box := range f.Boxes {
+        v.Boxes = append(v.Boxes, NewBoxJSON(box))
+    }
+    if moov, err := f.Moov(); err == nil {
+        for _, trak := range moov.Tracks() {
+            v.Tracks = append(v.Tracks, NewTrackJSON(moov, trak))
+        }
+    }
+    if mime, err := f.MimeType(); err == nil {
+        v.MimeType = mime
+    }
+    return v
+}
+
+func (v *File) MarshalJSON() ([]byte, error) {
+    return json.Marshal(NewFileJSON(v))
+}
+
+func (v *Mp4FileTypeBox) Fields() map[string]interface{} {
+    brands := []string{}
+    for _, brand := range v.CompatibleBrands {
+        brands = append(brands, FourCC(brand))
+    }
+    return map[string]interface{}{
+        "major_brand": FourCC(v.MajorBrand),
+        "minor_version": v.MinorVersion,
+        "compatible_brands": brands,
+    }
+}
+
+func (v *Mp4MovieHeaderBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "creation_time": v.CreateTime,
+        "modification_time": v.ModTime,
+        "timescale": v.TimeScale,
+        "duration": v.DurationInTbn,
+        "rate": v.Rate,
+        "volume": v.Volume,
+        "matrix": v.Matrix,
+        "next_track_id": v.NextTrackId,
+    }
+}
+
+func (v *Mp4TrackHeaderBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "creation_time": v.CreateTime,
+        "modification_time": v.ModTime,
+        "track_id": v.TrackId,
+        "duration": v.Duration,
+        "layer": v.Layer,
+        "alternate_group": v.AlternateGroup,
+        "volume": v.Volume,
+        "matrix": v.Matrix,
+        "width": v.Width,
+        "height": v.Height,
+    }
+}
+
+func (v *Mp4EditListBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "entry_count": v.EntryCount,
+        "entries": v.Entries,
+    }
+}
+
+func (v *Mp4MediaHeaderBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "creation_time": v.CreateTime,
+        "modification_time": v.ModTime,
+        "timescale": v.TimeScale,
+        "duration": v.Duration,
+        "language": v.LanguageCode(),
+    }
+}
+
+func (v *Mp4HandlerReferenceBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "handler_type": FourCC(v.HandlerType),
+        "name": strings.TrimRight(v.Name, "\x00"),
+    }
+}
+
+func (v *Mp4VideoMediaHeaderBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "graphics_mode": v.GraphicsMode,
+        "opcolor": v.Opcolor,
+    }
+}
+
+func (v *Mp4SoundMediaHeaderBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "balance": v.Balance,
+    }
+}
+
+func (v *Mp4HintMediaHeaderBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "max_pdu_size": v.MaxPduSize,
+        "avg_pdu_size": v.AvgPduSize,
+        "max_bitrate": v.MaxBitrate,
+        "avg_bitrate": v.AvgBitrate,
+    }
+}
+
+func (v *Mp4DataReferenceBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "entry_count": v.EntryCount,
+    }
+}
+
+func (v *Mp4DataEntryUrlBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "self_contained": v.SelfContained(),
+        "location": strings.TrimRight(v.Location, "\x00"),
+    }
+}
+
+func (v *Mp4DataEntryUrnBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "self_contained": v.SelfContained(),
+        "name": strings.TrimRight(v.Name, "\x00"),
+        "location": strings.TrimRight(v.Location, "\x00"),
+    }
+}
+
+func (v *Mp4SampleDescritionBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "entry_count": len(v.Entries),
+    }
+}
+
+func (v *Mp4VisualSampleEntry) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "data_reference_index": v.DataReferenceIndex,
+        "width": v.Width,
+        "height": v.Height,
+        "horiz_resolution": v.HorizResolution,
+        "vert_resolution": v.VertResolution,
+        "frame_count": v.FrameCount,
+        "compressor_name": v.Compressor(),
+        "depth": v.Depth,
+    }
+}
+
+func (v *Mp4AvccBox) Fields() map[string]interface{} {
+    fields := map[string]interface{}{
+        "avc_config": hex.EncodeToString(v.AvcConfig),
+        "configuration_version": v.ConfigurationVersion,
+        "avc_profile_indication": v.AvcProfileIndication,
+        "profile_compatibility": v.ProfileCompatibility,
+        "avc_level_indication": v.AvcLevelIndication,
+        "length_size_minus_one": v.LengthSizeMinusOne,
+        "sequence_parameter_sets": hexNalus(v.SequenceParameterSets),
+        "picture_parameter_sets": hexNalus(v.PictureParameterSets),
+    }
+    if v.HighProfileExtension {
+        fields["chroma_format"] = v.ChromaFormat
+        fields["bit_depth_luma"] = v.BitDepthLumaMinus8 + 8
+        fields["bit_depth_chroma"] = v.BitDepthChromaMinus8 + 8
+        fields["sequence_parameter_set_exts"] = hexNalus(v.SequenceParameterSetExts)
+    }
+    if sps, err := v.Sps(); err == nil {
+        sarWidth, sarHeight := sps.SampleAspectRatio()
+        fields["sps"] = map[string]interface{}{
+            "profile_idc": sps.ProfileIdc,
+            "constraint_flags": sps.ConstraintFlags,
+            "level_idc": sps.LevelIdc,
+            "chroma_format_idc": sps.ChromaFormatIdc,
+            "bit_depth_luma": sps.BitDepthLumaMinus8 + 8,
+            "bit_depth_chroma": sps.BitDepthChromaMinus8 + 8,
+            "width": sps.Width(),
+            "height": sps.Height(),
+            "frame_mbs_only": sps.FrameMbsOnlyFlag,
+            "max_num_ref_frames": sps.MaxNumRefFrames,
+            "sar_width": sarWidth,
+            "sar_height": sarHeight,
+            "video_full_range": sps.VideoFullRangeFlag,
+            "colour_primaries": sps.ColourPrimaries,
+            "transfer_characteristics": sps.TransferCharacteristics,
+            "matrix_coefficients": sps.MatrixCoefficients,
+            "num_units_in_tick": sps.NumUnitsInTick,
+            "time_scale": sps.TimeScale,
+            "fixed_frame_rate": sps.FixedFrameRateFlag,
+            "frame_rate": sps.FrameRate(),
+        }
+    }
+    return fields
+}
+
+func hexNalus(nalus [][]uint8) []string {
+    v := []string{}
+    for _, nalu := range nalus {
+        v = append(v, hex.EncodeToString(nalu))
+    }
+    return v
+}
+
+func (v *Mp4HvccBox) Fields() map[string]interface{} {
+    arrays := []map[string]interface{}{}
+    for _, array := range v.Arrays {
+        arrays = append(arrays, map[string]interface{}{
+            "array_completeness": array.ArrayCompleteness,
+            "nal_unit_type": array.NaluType,
+            "nalus": hexNalus(array.Nalus),
+        })
+    }
+    return map[string]interface{}{
+        "configuration_version": v.ConfigurationVersion,
+        "general_profile_space": v.GeneralProfileSpace,
+        "general_tier_flag": v.GeneralTierFlag,
+        "general_profile_idc": v.GeneralProfileIdc,
+        "general_profile_compatibility_flags": v.GeneralProfileCompatibilityFlags,
+        "general_constraint_indicator_flags": v.GeneralConstraintIndicatorFlags,
+        "general_level_idc": v.GeneralLevelIdc,
+        "min_spatial_segmentation_idc": v.MinSpatialSegmentationIdc,
+        "parallelism_type": v.ParallelismType,
+        "chroma_format_idc": v.ChromaFormatIdc,
+        "bit_depth_luma": v.BitDepthLumaMinus8 + 8,
+        "bit_depth_chroma": v.BitDepthChromaMinus8 + 8,
+        "avg_frame_rate": v.AvgFrameRate,
+        "constant_frame_rate": v.ConstantFrameRate,
+        "num_temporal_layers": v.NumTemporalLayers,
+        "temporal_id_nested": v.TemporalIdNested,
+        "length_size_minus_one": v.LengthSizeMinusOne,
+        "arrays": arrays,
+    }
+}
+
+func (v *Mp4Av1cBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "version": v.Version,
+        "seq_profile": v.SeqProfile,
+        "seq_level_idx_0": v.SeqLevelIdx0,
+        "seq_tier_0": v.SeqTier0,
+        "bit_depth": v.BitDepth(),
+        "monochrome": v.Monochrome,
+        "chroma_subsampling_x": v.ChromaSubsamplingX,
+        "chroma_subsampling_y": v.ChromaSubsamplingY,
+        "chroma_sample_position": v.ChromaSamplePosition,
+        "initial_presentation_delay_present": v.InitialPresentationDelayPresent,
+        "initial_presentation_delay_minus_one": v.InitialPresentationDelayMinusOne,
+        "config_obus": hex.EncodeToString(v.ConfigOBUs),
+    }
+}
+
+func (v *Mp4VpccBox) Fields() map[string]interface{} {
+    if v.Version != 1 {
+        return map[string]interface{}{
+            "data": hex.EncodeToString(v.Data),
+        }
+    }
+    return map[string]interface{}{
+        "profile": v.Profile,
+        "level": v.Level,
+        "bit_depth": v.BitDepth,
+        "chroma_subsampling": v.ChromaSubsampling,
+        "video_full_range_flag": v.VideoFullRangeFlag,
+        "colour_primaries": v.ColourPrimaries,
+        "transfer_characteristics": v.TransferCharacteristics,
+        "matrix_coefficients": v.MatrixCoefficients,
+        "codec_initialization_data": hex.EncodeToString(v.CodecInitializationData),
+    }
+}
+
+func (v *Mp4AudioSampleEntry) Fields() map[string]interface{} {
+    fields := map[string]interface{}{
+        "data_reference_index": v.DataReferenceIndex,
+        "channel_count": v.ChannelCount,
+        "sample_size": v.SampleSize,
+        "sample_rate": v.SampleRate >> 16,
+    }
+    switch v.SoundVersion() {
+    case 1:
+        fields["sound_version"] = 1
+        fields["samples_per_packet"] = v.SamplesPerPacket
+        fields["bytes_per_packet"] = v.BytesPerPacket
+        fields["bytes_per_frame"] = v.BytesPerFrame
+        fields["bytes_per_sample"] = v.BytesPerSample
+    case 2:
+        fields["sound_version"] = 2
+        fields["audio_sample_rate"] = v.AudioSampleRate
+        fields["num_audio_channels"] = v.NumAudioChannels
+        fields["const_bits_per_channel"] = v.ConstBitsPerChannel
+        fields["format_specific_flags"] = v.FormatSpecificFlags
+        fields["const_bytes_per_audio_packet"] = v.ConstBytesPerAudioPacket
+        fields["const_lpcm_frames_per_audio_packet"] = v.ConstLPCMFramesPerAudioPacket
+    }
+    if v.IsPCM() {
+        fields["bits_per_sample"] = v.BitsPerSample()
+        fields["endianness"] = endianness(v.LittleEndian())
+    }
+    return fields
+}
+
+func endianness(littleEndian bool) string {
+    if littleEndian {
+        return "little"
+    }
+    return "big"
+}
+
+func (v *Mp4OpusSpecificBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "version": v.Version,
+        "output_channel_count": v.OutputChannelCount,
+        "pre_skip": v.PreSkip,
+        "input_sample_rate": v.InputSampleRate,
+        "output_gain": v.OutputGain,
+        "channel_mapping_family": v.ChannelMappingFamily,
+        "stream_count": v.StreamCount,
+        "coupled_count": v.CoupledCount,
+        "channel_mapping": hex.EncodeToString(v.ChannelMapping),
+    }
+}
+
+func (v *Mp4Ac3SpecificBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "fscod": v.Fscod,
+        "bsid": v.Bsid,
+        "bsmod": v.Bsmod,
+        "acmod": v.Acmod,
+        "lfeon": v.Lfeon,
+        "bit_rate_code": v.BitRateCode,
+    }
+}
+
+func (v *Mp4Ec3SpecificBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "data_rate": v.DataRate,
+        "num_ind_sub": len(v.Substreams) - 1,
+        "substreams": v.Substreams,
+        "flag_ec3_extension_type_a": v.FlagEc3ExtensionTypeA,
+        "complexity_index_type_a": v.ComplexityIndexTypeA,
+    }
+}
+
+func (v *Mp4FlacSpecificBox) Fields() map[string]interface{} {
+    blocks := []map[string]interface{}{}
+    for _, block := range v.Blocks {
+        blocks = append(blocks, map[string]interface{}{
+            "last_metadata_block_flag": block.LastMetadataBlockFlag,
+            "block_type": block.BlockType,
+            "length": len(block.Data),
+        })
+    }
+    fields := map[string]interface{}{
+        "blocks": blocks,
+    }
+    if si := v.StreamInfo; si != nil {
+        fields["streaminfo"] = map[string]interface{}{
+            "min_block_size": si.MinBlockSize,
+            "max_block_size": si.MaxBlockSize,
+            "min_frame_size": si.MinFrameSize,
+            "max_frame_size": si.MaxFrameSize,
+            "sample_rate": si.SampleRate,
+            "channels": si.Channels,
+            "bits_per_sample": si.BitsPerSample,
+            "total_samples": si.TotalSamples,
+            "md5": hex.EncodeToString(si.Md5),
+        }
+    }
+    return fields
+}
+
+func (v *Mp4PcmConfigurationBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "format_flags": v.FormatFlags,
+        "pcm_sample_size": v.PcmSampleSize,
+    }
+}
+
+func (v *Mp4EsdsBox) Fields() map[string]interface{} {
+    dcd := v.es.decConfigDescr
+    fields := map[string]interface{}{
+        "es_id": v.es.ES_ID,
+        "object_type_indication": dcd.objectTypeIndication,
+        "stream_type": dcd.streamType,
+        "buffer_size_db": dcd.bufferSizeDB,
+        "max_bitrate": dcd.maxBitrate,
+        "avg_bitrate": dcd.avgBitrate,
+        "decoder_specific_info": hex.EncodeToString(dcd.descSpecificInfo.Asc),
+    }
+    if aac, err := dcd.descSpecificInfo.AacConfig(); err == nil && dcd.objectTypeIndication == SrsMp4ObjectTypeAac {
+        fields["audio_specific_config"] = aac.Fields()
+    }
+    return fields
+}
+
+func (v *Mp4AacConfig) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "object_type": v.ObjectType,
+        "sampling_frequency_index": v.SamplingFrequencyIndex,
+        "sampling_frequency": v.SamplingFrequency,
+        "channel_configuration": v.ChannelConfiguration,
+        "sbr": v.Sbr,
+        "extension_sampling_frequency": v.ExtensionSamplingFrequency,
+        "ps": v.Ps,
+        "profile": v.Profile(),
+        "description": v.String(),
+    }
+}
+
+func (v *Mp4DecodingTime2SampleBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "entry_count": v.EntryCount,
+        "entries": v.Entries,
+    }
+}
+
+func (v *Mp4CompositionTime2SampleBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "entry_count": v.EntryCount,
+        "entries": v.Entries,
+    }
+}
+
+func (v *Mp4SyncSampleBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "entry_count": v.EntryCount,
+        "sample_numbers": v.SampleNumbers,
+    }
+}
+
+func (v *Mp4Sample2ChunkBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "entry_count": v.EntryCount,
+        "entries": v.Entries,
+    }
+}
+
+func (v *Mp4SampleSizeBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "sample_size": v.SampleSize,
+        "sample_count": v.SampleCount,
+        "entry_sizes": v.EntrySizes,
+    }
+}
+
+func (v *Mp4CompactSampleSizeBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "field_size": v.FieldSize,
+        "sample_count": v.SampleCount,
+        "entry_sizes": v.EntrySizes,
+    }
+}
+
+func (v *Mp4ChunkOffsetBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "entry_count": v.EntryCount,
+        "chunk_offsets": v.Entries,
+    }
+}
+
+func (v *Mp4ChunkLargeOffsetBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "entry_count": v.EntryCount,
+        "chunk_offsets": v.Entries,
+    }
+}
+
+func (v *Mp4UserDataBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "data_size": v.NbData,
+    }
+}
+
+func (v *Mp4MediaDataBox) Fields() map[string]interface{} {
+    return map[string]interface{}{
+        "data_offset": v.DataOffset,
+        "data_size": v.NbData,
+    }
+}
@@ -0,0 +1,98 @@
+package mp4
+
+import (
+    "encoding/json"
+    "testing"
+)
+
+// Marshal the file and decode it to the generic json, as the consumer of the output.
+func marshalFile(t *testing.T, f *File) map[string]interface{} {
+    t.Helper()
+    b, err := json.Marshal(f)
+    if err != nil {
+        t.Fatalf("marshal failed, err is %v", err)
+    }
+    var v map[string]interface{}
+    if err = json.Unmarshal(b, &v); err != nil {
+        t.Fatalf("unmarshal %s failed, err is %v", b, err)
+    }
+    return v
+}
+
+// Get the json of box by the path of types, for example, "moov", "trak", "tkhd".
+func findJSON(t *testing.T, boxes interface{}, types ...string) map[string]interface{} {
+    t.Helper()
+    var box map[string]interface{}
+    for _, bt := range types {
+        box = nil
+        for _, b := range boxes.([]interface{}) {
+            if b := b.(map[string]interface{}); b["type"] == bt {
+                box = b
+                break
+            }
+        }
+        if box == nil {
+            t.Fatalf("no box %v of %v", bt, types)
+        }
+        boxes = box["boxes"]
+    }
+    return box
+}
+
+func TestFileJSON(t *testing.T) {
+    b := buildAvFile(false)
+    v := marshalFile(t, parseFile(t, b))
+
+    ftyp := findJSON(t, v["boxes"], "ftyp")
+    if ftyp["offset"] != 0.0 || ftyp["size"] != 32.0 || ftyp["header_size"] != 8.0 {
+        t.Errorf("ftyp %v", ftyp)
+    }
+    fields := ftyp["fields"].(map[string]interface{})
+    if fields["major_brand"] != "isom" || len(fields["compatible_brands"].([]interface{})) != 4 {
+        t.Errorf("ftyp fields %v", fields)
+    }
+
+    tkhd := findJSON(t, v["boxes"], "moov", "trak", "tkhd")
+    if tkhd["version"] != 0.0 || tkhd["flags"] != 3.0 {
+        t.Errorf("tkhd version and flags %v", tkhd)
+    }
+    if fields := tkhd["fields"].(map[string]interface{}); fields["track_id"] != 1.0 || fields["width"] != float64(1920 << 16) {
+        t.Errorf("tkhd fields %v", fields)
+    }
+
+    // The full boxes only have version and flags, and the entries of stsd are the boxes.
+    if moov := findJSON(t, v["boxes"], "moov"); moov["version"] != nil || moov["flags"] != nil {
+        t.Errorf("moov is not full box %v", moov)
+    }
+    if stsd := findJSON(t, v["boxes"], "moov", "trak", "mdia", "minf", "stbl", "stsd"); len(stsd["boxes"].([]interface{})) != 1 {
+        t.Errorf("stsd entries %v", stsd["boxes"])
+    }
+
+    mdat := findJSON(t, v["boxes"], "mdat")
+    if mdat["offset"] != float64(len(b)) - mdat["size"].(float64) {
+        t.Errorf("mdat %v of file %v", mdat, len(b))
+    }
+
+    tracks := v["tracks"].([]interface{})
+    if len(tracks) != 2 {
+        t.Fatalf("tracks %v", tracks)
+    }
+    video, audio := tracks[0].(map[string]interface{}), tracks[1].(map[string]interface{})
+    if video["handler"] != "vide" || video["codec"] != "avc1" || video["width"] != 1920.0 || video["sample_count"] != 10.0 {
+        t.Errorf("video %v", video)
+    }
+    if audio["handler"] != "soun" || audio["codec"] != "mp4a" || audio["channel_count"] != 2.0 || audio["sample_rate"] != 48000.0 {
+        t.Errorf("audio %v", audio)
+    }
+}
+
+func TestUuidJSON(t *testing.T) {
+    userType := []byte("0123456789abcdef")
+    b := append(buildAvFile(false), box("uuid", userType, []byte{1, 2, 3})...)
+    v := marshalFile(t, parseFile(t, b))
+
+    uuid := findJSON(t, v["boxes"], "uuid")
+    if uuid["user_type"] != "30313233343536373839616263646566" || uuid["header_size"] != 24.0 || uuid["size"] != 27.0 {
+        t.Errorf("uuid %v", uuid)
+    }
+}
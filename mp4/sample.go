@@ -0,0 +1,327 @@
+package mp4
+
+import (
+    "fmt"
+    "io"
+    "math"
+    "math/bits"
+    "sort"
+)
+
+// The sample of track, resolved from the sample table, see Mp4TrackBox.Samples.
+type Mp4Sample struct {
+    // The index of sample in track, starts from 0.
+    Index int `json:"index"`
+    // The position and size of sample in file.
+    Offset uint64 `json:"offset"`
+    Size uint32 `json:"size"`
+    // The decoding and composition time, and duration, in the timescale of mdhd.
+    Dts uint64 `json:"dts"`
+    Pts int64 `json:"pts"`
+    Duration uint32 `json:"duration"`
+    // Whether sync sample, every sample is sync when there is no stss.
+    Keyframe bool `json:"keyframe"`
+    // The index of chunk, starts from 1, and the index of sample entry in stsd, starts from 1.
+    Chunk uint32 `json:"chunk"`
+    SampleDescriptionIndex uint32 `json:"sample_description_index"`
+}
+
+// Expand the sample table to the list of samples, with the stsc, stco or co64, stsz or stz2 for the position,
+// the stts, ctts for the time, and the stss for the keyframe.
+// @remark The samples are cached after the first call.
+func (v *Mp4TrackBox) Samples() (samples []*Mp4Sample, err error) {
+    if v.samples != nil {
+        return v.samples, nil
+    }
+    if samples, err = v.resolveSamples(); err != nil {
+        return
+    }
+    v.samples = samples
+    return
+}
+
+func (v *Mp4TrackBox) resolveSamples() (samples []*Mp4Sample, err error) {
+    // The chunk offsets are in the other file, not supported.
+    if locations := v.ExternalData(); len(locations) > 0 {
+        return nil, fmt.Errorf("track references external data %v", locations)
+    }
+
+    var stsz SampleSizeBox
+    if stsz, err = v.Stsz(); err != nil {
+        return
+    }
+    var stco ChunkOffsetBox
+    if stco, err = v.Stco(); err != nil {
+        return
+    }
+    var stsc *Mp4Sample2ChunkBox
+    if stsc, err = v.Stsc(); err != nil {
+        return
+    }
+    var stts *Mp4DecodingTime2SampleBox
+    if stts, err = v.Stts(); err != nil {
+        return
+    }
+
+    // The ctts and stss are optional.
+    ctts, _ := v.Ctts()
+    stss, _ := v.Stss()
+
+    // The first chunk starts from 1 and increases, or the chunk index overflows.
+    nbChunks := uint32(stco.NbChunks())
+    for i, entry := range stsc.Entries {
+        if entry.FirstChunk < 1 {
+            return nil, fmt.Errorf("invalid first chunk %v of stsc entry %v", entry.FirstChunk, i)
+        }
+        if i > 0 && entry.FirstChunk <= stsc.Entries[i - 1].FirstChunk {
+            return nil, fmt.Errorf("first chunk %v of stsc entry %v not increasing, previous is %v", entry.FirstChunk, i, stsc.Entries[i - 1].FirstChunk)
+        }
+    }
+
+    // The sample count is not trusted, so never allocate more than the chunks hold.
+    nbSamples := stsz.NbSamples()
+    samples = make([]*Mp4Sample, 0, stsc.nbSamples(nbChunks, uint64(nbSamples)))
+
+    // The position, by the chunks of stsc and stco.
+    for i, entry := range stsc.Entries {
+        last := nbChunks
+        if i < len(stsc.Entries) - 1 {
+            last = stsc.Entries[i + 1].FirstChunk - 1
+        }
+
+        for chunk := entry.FirstChunk; chunk <= last && chunk <= nbChunks; chunk++ {
+            offset := stco.ChunkOffset(int(chunk - 1))
+            for j := uint32(0); j < entry.SamplesPerChunk && len(samples) < nbSamples; j++ {
+                sample := &Mp4Sample{
+                    Index: len(samples),
+                    Offset: offset,
+                    Size: stsz.EntrySize(len(samples)),
+                    Keyframe: stss == nil,
+                    Chunk: chunk,
+                    SampleDescriptionIndex: entry.SampleDescriptionIndex,
+                }
+                offset += uint64(sample.Size)
+                samples = append(samples, sample)
+            }
+        }
+    }
+    if len(samples) != nbSamples {
+        return nil, fmt.Errorf("stsc and stco has %v samples, stsz has %v", len(samples), nbSamples)
+    }
+
+    // The dts and duration, by stts.
+    var index int
+    var dts uint64
+    for _, entry := range stts.Entries {
+        for j := uint32(0); j < entry.SampleCount && index < nbSamples; j++ {
+            samples[index].Dts, samples[index].Pts = dts, int64(dts)
+            samples[index].Duration = entry.SampleDelta
+            dts += uint64(entry.SampleDelta)
+            index++
+        }
+    }
+    if index != nbSamples {
+        return nil, fmt.Errorf("stts has %v samples, stsz has %v", index, nbSamples)
+    }
+
+    // The pts, by ctts, CT(n) = DT(n) + CTTS(n).
+    if ctts != nil {
+        index = 0
+        for _, entry := range ctts.Entries {
+            for j := uint32(0); j < entry.SampleCount && index < nbSamples; j++ {
+                samples[index].Pts = int64(samples[index].Dts) + entry.SampleOffset
+                index++
+            }
+        }
+    }
+
+    // The keyframe, by stss, the sample number starts from 1.
+    if stss != nil {
+        for _, number := range stss.SampleNumbers {
+            if number >= 1 && int(number) <= nbSamples {
+                samples[number - 1].Keyframe = true
+            }
+        }
+    }
+    return
+}
+
+// Get the number of samples in the chunks, by the stsc, at most max.
+func (v *Mp4Sample2ChunkBox) nbSamples(nbChunks uint32, max uint64) (n uint64) {
+    for i, entry := range v.Entries {
+        last := nbChunks
+        if i < len(v.Entries) - 1 && v.Entries[i + 1].FirstChunk - 1 < last {
+            last = v.Entries[i + 1].FirstChunk - 1
+        }
+        if entry.FirstChunk <= last {
+            n += uint64(last - entry.FirstChunk + 1) * uint64(entry.SamplesPerChunk)
+        }
+        if n >= max {
+            return max
+        }
+    }
+    return
+}
+
+// Get the sample at the dts, in the timescale of mdhd, that is the last sample whose dts is not
+// greater than it.
+func (v *Mp4TrackBox) FindSample(dts uint64) (*Mp4Sample, error) {
+    samples, err := v.Samples()
+    if err != nil {
+        return nil, err
+    }
+    if len(samples) == 0 || samples[0].Dts > dts {
+        return nil, fmt.Errorf("no sample at dts %v", dts)
+    }
+
+    index := sort.Search(len(samples), func(i int) bool {
+        return samples[i].Dts > dts
+    })
+    return samples[index - 1], nil
+}
+
+// Read the sample of index, starts from 0, from r which is the whole file.
+func (v *Mp4TrackBox) ReadSample(r io.ReaderAt, index int) ([]uint8, error) {
+    samples, err := v.Samples()
+    if err != nil {
+        return nil, err
+    }
+    if index < 0 || index >= len(samples) {
+        return nil, fmt.Errorf("sample %v out of range, nb samples=%v", index, len(samples))
+    }
+    return ReadSample(r, samples[index])
+}
+
+// Read the sample from r which is the whole file. The sample must be in the file, which is checked
+// by the size of r when known, for example, bytes.Reader or io.SectionReader, or by reading in chunks
+// otherwise, so the corrupted stsz never allocates more than the file.
+func ReadSample(r io.ReaderAt, sample *Mp4Sample) (data []uint8, err error) {
+    if sample.Offset > math.MaxInt64 - uint64(sample.Size) {
+        return nil, fmt.Errorf("sample %v at %v size %v overflow", sample.Index, sample.Offset, sample.Size)
+    }
+    sr, sized := r.(interface{ Size() int64 })
+    if sized && sample.Offset + uint64(sample.Size) > uint64(sr.Size()) {
+        return nil, fmt.Errorf("sample %v at %v size %v overflow the file %v", sample.Index, sample.Offset, sample.Size, sr.Size())
+    }
+
+    section := io.NewSectionReader(r, int64(sample.Offset), int64(sample.Size))
+    if sized {
+        data = make([]uint8, sample.Size)
+        _, err = io.ReadFull(section, data)
+    } else if data, err = io.ReadAll(section); err == nil && len(data) < int(sample.Size) {
+        err = io.ErrUnexpectedEOF
+    }
+    if err != nil {
+        return nil, fmt.Errorf("read sample %v at %v failed, size=%v, err is %v", sample.Index, sample.Offset, sample.Size, err)
+    }
+    return data, nil
+}
+
+// The iterator to walk the samples of all tracks interleaved, in the order of file or dts.
+type Mp4SampleIterator struct {
+    r io.ReaderAt
+    order int
+    tracks []*Mp4TrackBox
+    timescales []uint64
+    samples [][]*Mp4Sample
+    // The index of next sample for each track.
+    next []int
+}
+
+// Create the iterator of the tracks in moov, the order is SrsMp4SampleOrderFile or SrsMp4SampleOrderDts.
+func NewMp4SampleIterator(r io.ReaderAt, moov *Mp4MovieBox, order int) (*Mp4SampleIterator, error) {
+    v := &Mp4SampleIterator{
+        r: r,
+        order: order,
+    }
+
+    for _, trak := range moov.Tracks() {
+        samples, err := trak.Samples()
+        if err != nil {
+            return nil, err
+        }
+
+        timescale := uint64(1)
+        if mdhd, err := trak.Mdhd(); err == nil && mdhd.TimeScale > 0 {
+            timescale = uint64(mdhd.TimeScale)
+        }
+
+        v.tracks = append(v.tracks, trak)
+        v.timescales = append(v.timescales, timescale)
+        v.samples = append(v.samples, samples)
+        v.next = append(v.next, 0)
+    }
+    return v, nil
+}
+
+// Get the next sample and its data, io.EOF when all samples are walked.
+func (v *Mp4SampleIterator) Next() (trak *Mp4TrackBox, sample *Mp4Sample, data []uint8, err error) {
+    selected := -1
+    for i := range v.tracks {
+        if v.next[i] >= len(v.samples[i]) {
+            continue
+        }
+        if selected < 0 || v.before(i, selected) {
+            selected = i
+        }
+    }
+    if selected < 0 {
+        return nil, nil, nil, io.EOF
+    }
+
+    trak, sample = v.tracks[selected], v.samples[selected][v.next[selected]]
+    v.next[selected]++
+
+    data, err = ReadSample(v.r, sample)
+    return
+}
+
+// Whether the next sample of track a is before the one of track b.
+func (v *Mp4SampleIterator) before(a, b int) bool {
+    sa, sb := v.samples[a][v.next[a]], v.samples[b][v.next[b]]
+    if v.order == SrsMp4SampleOrderFile {
+        return sa.Offset < sb.Offset
+    }
+
+    // Compare sa.Dts/tsa < sb.Dts/tsb, by sa.Dts*tsb < sb.Dts*tsa in 128bits.
+    ha, la := bits.Mul64(sa.Dts, v.timescales[b])
+    hb, lb := bits.Mul64(sb.Dts, v.timescales[a])
+    return ha < hb || (ha == hb && la < lb)
+}
+
+// Get the average bitrate in bits per second, by the sizes of samples and the duration of mdhd.
+func (v *Mp4TrackBox) Bitrate() (bitrate uint64, err error) {
+    var mdhd *Mp4MediaHeaderBox
+    if mdhd, err = v.Mdhd(); err != nil {
+        return
+    }
+    var stsz SampleSizeBox
+    if stsz, err = v.Stsz(); err != nil {
+        return
+    }
+    if mdhd.Duration == 0 {
+        return 0, fmt.Errorf("no duration in mdhd")
+    }
+
+    var size uint64
+    for i := 0; i < stsz.NbSamples(); i++ {
+        size += uint64(stsz.EntrySize(i))
+    }
+    return rescale(size * 8, mdhd.Duration, uint64(mdhd.TimeScale)), nil
+}
+
+// Get the average frame rate, by the number of samples and the duration of mdhd.
+func (v *Mp4TrackBox) FrameRate() (fps float64, err error) {
+    var mdhd *Mp4MediaHeaderBox
+    if mdhd, err = v.Mdhd(); err != nil {
+        return
+    }
+    var stsz SampleSizeBox
+    if stsz, err = v.Stsz(); err != nil {
+        return
+    }
+    if mdhd.Duration == 0 {
+        return 0, fmt.Errorf("no duration in mdhd")
+    }
+    return float64(stsz.NbSamples()) * float64(mdhd.TimeScale) / float64(mdhd.Duration), nil
+}
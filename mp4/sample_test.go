@@ -0,0 +1,366 @@
+package mp4
+
+import (
+    "bytes"
+    "encoding/binary"
+    "io"
+    "runtime"
+    "strings"
+    "testing"
+)
+
+// Build the track of the boxes of stbl, the timescale of mdhd is 1000.
+func buildTrak(t *testing.T, stbl ...[]byte) *Mp4TrackBox {
+    t.Helper()
+    mdhd := fullBox("mdhd", 0, 0, be(uint32(0), uint32(0), uint32(1000), uint32(0), uint16(0x55c4), uint16(0)))
+    hdlr := fullBox("hdlr", 0, 0, be(uint32(0), []byte("vide")), make([]byte, 12), []byte{0})
+    dinf := box("dinf", fullBox("dref", 0, 0, be(uint32(1)), fullBox("url ", 0, SRS_MP4_DATA_ENTRY_SELF_CONTAINED)))
+    stsd := fullBox("stsd", 0, 0, be(uint32(1)), visualEntry("avc1", 16, 16))
+    trak := box("trak", box("mdia", mdhd, hdlr, box("minf", dinf, box("stbl", append([][]byte{stsd}, stbl...)...))))
+    return parseFile(t, trak).Boxes[0].(*Mp4TrackBox)
+}
+
+func stsc(entries ...[3]uint32) []byte {
+    return fullBox("stsc", 0, 0, be(uint32(len(entries)), entries))
+}
+
+func stco(offsets ...uint32) []byte {
+    return fullBox("stco", 0, 0, be(uint32(len(offsets)), offsets))
+}
+
+func stsz(sizes ...uint32) []byte {
+    return fullBox("stsz", 0, 0, be(uint32(0), uint32(len(sizes)), sizes))
+}
+
+func stts(entries ...[2]uint32) []byte {
+    return fullBox("stts", 0, 0, be(uint32(len(entries)), entries))
+}
+
+func TestResolveSamples(t *testing.T) {
+    cases := []struct {
+        name string
+        stbl [][]byte
+        // The offset, size, dts, pts, keyframe, chunk and sample description index.
+        samples [][7]int64
+    }{
+        {"one entry", [][]byte{stsc([3]uint32{1, 2, 1}), stco(100, 200), stsz(10, 20, 30, 40), stts([2]uint32{4, 10})},
+            [][7]int64{{100, 10, 0, 0, 1, 1, 1}, {110, 20, 10, 10, 1, 1, 1}, {200, 30, 20, 20, 1, 2, 1}, {230, 40, 30, 30, 1, 2, 1}}},
+        {"entries", [][]byte{stsc([3]uint32{1, 1, 1}, [3]uint32{2, 2, 2}), stco(100, 200, 300), stsz(1, 2, 3, 4, 5), stts([2]uint32{2, 10}, [2]uint32{3, 20})},
+            [][7]int64{{100, 1, 0, 0, 1, 1, 1}, {200, 2, 10, 10, 1, 2, 2}, {202, 3, 20, 20, 1, 2, 2}, {300, 4, 40, 40, 1, 3, 2}, {304, 5, 60, 60, 1, 3, 2}}},
+        {"ctts and stss", [][]byte{stsc([3]uint32{1, 3, 1}), stco(8), stsz(1, 1, 1), stts([2]uint32{3, 10}),
+            fullBox("ctts", 0, 0, be(uint32(2), uint32(1), uint32(20), uint32(2), uint32(10))),
+            fullBox("stss", 0, 0, be(uint32(2), uint32(1), uint32(3)))},
+            [][7]int64{{8, 1, 0, 20, 1, 1, 1}, {9, 1, 10, 20, 0, 1, 1}, {10, 1, 20, 30, 1, 1, 1}}},
+        {"negative ctts", [][]byte{stsc([3]uint32{1, 2, 1}), stco(8), stsz(1, 1), stts([2]uint32{2, 10}),
+            fullBox("ctts", 1, 0, be(uint32(1), uint32(2), int32(-10)))},
+            [][7]int64{{8, 1, 0, -10, 1, 1, 1}, {9, 1, 10, 0, 1, 1, 1}}},
+        {"constant size", [][]byte{stsc([3]uint32{1, 2, 1}), stco(8, 100), fullBox("stsz", 0, 0, be(uint32(4), uint32(3))), stts([2]uint32{3, 1})},
+            [][7]int64{{8, 4, 0, 0, 1, 1, 1}, {12, 4, 1, 1, 1, 1, 1}, {100, 4, 2, 2, 1, 2, 1}}},
+        {"co64", [][]byte{stsc([3]uint32{1, 1, 1}), fullBox("co64", 0, 0, be(uint32(2), uint64(1) << 32, uint64(5) << 32)), stsz(1, 2), stts([2]uint32{2, 1})},
+            [][7]int64{{1 << 32, 1, 0, 0, 1, 1, 1}, {5 << 32, 2, 1, 1, 1, 2, 1}}},
+    }
+    for _, c := range cases {
+        samples, err := buildTrak(t, c.stbl...).Samples()
+        if err != nil {
+            t.Errorf("%v: %v", c.name, err)
+            continue
+        }
+        if len(samples) != len(c.samples) {
+            t.Errorf("%v: %v samples, expect %v", c.name, len(samples), len(c.samples))
+            continue
+        }
+        for i, s := range samples {
+            var keyframe int64
+            if s.Keyframe {
+                keyframe = 1
+            }
+            got := [7]int64{int64(s.Offset), int64(s.Size), int64(s.Dts), s.Pts, keyframe, int64(s.Chunk), int64(s.SampleDescriptionIndex)}
+            if got != c.samples[i] || s.Index != i {
+                t.Errorf("%v: sample %v is %v, expect %v", c.name, i, got, c.samples[i])
+            }
+        }
+    }
+}
+
+func TestResolveSamplesInvalid(t *testing.T) {
+    cases := []struct {
+        name string
+        stbl [][]byte
+        err string
+    }{
+        {"first chunk 0", [][]byte{stsc([3]uint32{0, 1, 1}), stco(8), stsz(1), stts([2]uint32{1, 1})}, "invalid first chunk"},
+        {"first chunk not increasing", [][]byte{stsc([3]uint32{1, 1, 1}, [3]uint32{3, 1, 1}, [3]uint32{3, 2, 1}), stco(8, 9, 10), stsz(1, 1, 1), stts([2]uint32{3, 1})}, "not increasing"},
+        {"first chunk decreasing", [][]byte{stsc([3]uint32{2, 1, 1}, [3]uint32{1, 1, 1}), stco(8, 9), stsz(1, 1), stts([2]uint32{2, 1})}, "not increasing"},
+        {"more samples", [][]byte{stsc([3]uint32{1, 1, 1}), stco(8), stsz(1, 1), stts([2]uint32{2, 1})}, "stsc and stco"},
+        {"less stts", [][]byte{stsc([3]uint32{1, 2, 1}), stco(8), stsz(1, 1), stts([2]uint32{1, 1})}, "stts"},
+        // The huge sample count of constant size, which is never allocated.
+        {"huge count", [][]byte{stsc([3]uint32{1, 2, 1}), stco(8), fullBox("stsz", 0, 0, be(uint32(4), uint32(0xffffffff))), stts([2]uint32{2, 1})}, "stsc and stco"},
+        {"no stco", [][]byte{stsc([3]uint32{1, 1, 1}), stsz(1), stts([2]uint32{1, 1})}, "stco"},
+    }
+    for _, c := range cases {
+        if _, err := buildTrak(t, c.stbl...).Samples(); err == nil || !strings.Contains(err.Error(), c.err) {
+            t.Errorf("%v: err is %v, expect %v", c.name, err, c.err)
+        }
+    }
+}
+
+func TestFindSample(t *testing.T) {
+    trak := buildTrak(t, stsc([3]uint32{1, 4, 1}), stco(8), stsz(1, 1, 1, 1), stts([2]uint32{2, 10}, [2]uint32{2, 30}))
+    cases := []struct {
+        dts uint64
+        index int
+    }{
+        {0, 0}, {9, 0}, {10, 1}, {19, 1}, {20, 2}, {49, 2}, {50, 3}, {1000, 3},
+    }
+    for _, c := range cases {
+        if sample, err := trak.FindSample(c.dts); err != nil || sample.Index != c.index {
+            t.Errorf("dts %v is sample %+v, expect %v, err is %v", c.dts, sample, c.index, err)
+        }
+    }
+
+    if _, err := buildTrak(t, stsc(), stco(), stsz(), stts()).FindSample(0); err == nil {
+        t.Error("find sample of empty track should fail")
+    }
+}
+
+func trackId(t *testing.T, trak *Mp4TrackBox) uint32 {
+    t.Helper()
+    tkhd, err := trak.Tkhd()
+    if err != nil {
+        t.Fatal(err)
+    }
+    return tkhd.TrackId
+}
+
+func TestReadSample(t *testing.T) {
+    b := buildAvFile(false)
+    tracks := parseTracks(t, b)
+    r := bytes.NewReader(b)
+
+    for _, trak := range tracks {
+        id := uint8(trackId(t, trak))
+        samples, err := trak.Samples()
+        if err != nil {
+            t.Fatal(err)
+        }
+        for i := range samples {
+            data, err := trak.ReadSample(r, i)
+            if err != nil || !bytes.Equal(data, bytes.Repeat([]byte{id, uint8(i)}, i + 1)) {
+                t.Errorf("track %v sample %v is %v, err is %v", id, i, data, err)
+            }
+        }
+        if _, err := trak.ReadSample(r, len(samples)); err == nil {
+            t.Errorf("track %v sample %v out of range should fail", id, len(samples))
+        }
+        if _, err := trak.ReadSample(r, -1); err == nil {
+            t.Errorf("track %v sample -1 should fail", id)
+        }
+    }
+
+    // The last sample in the truncated file.
+    samples, _ := tracks[1].Samples()
+    last := samples[len(samples) - 1]
+    if _, err := ReadSample(bytes.NewReader(b[:len(b) - 1]), last); err == nil {
+        t.Error("read the truncated sample should fail")
+    }
+}
+
+// The reader without the size, for example, the file which is not a bytes.Reader.
+type readerAt struct {
+    io.ReaderAt
+}
+
+func TestReadSampleOversized(t *testing.T) {
+    // The size of the first video sample in stsz is about 4GB.
+    b := buildAvFile(false)
+    pos := bytes.Index(b, []byte("stsz"))
+    binary.BigEndian.PutUint32(b[pos + 16:], 0xfffffff0)
+    moov, err := parseFile(t, b).Moov()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    var before, after runtime.MemStats
+    runtime.ReadMemStats(&before)
+    for _, r := range []io.ReaderAt{bytes.NewReader(b), readerAt{bytes.NewReader(b)}} {
+        if _, err := moov.Tracks()[0].ReadSample(r, 0); err == nil {
+            t.Errorf("%T: the oversized sample should fail", r)
+        }
+
+        it, err := NewMp4SampleIterator(r, moov, SrsMp4SampleOrderFile)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if _, sample, _, err := it.Next(); err == nil || sample.Size != 0xfffffff0 {
+            t.Errorf("%T: the oversized sample %+v, err is %v", r, sample, err)
+        }
+    }
+    runtime.ReadMemStats(&after)
+    if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64 * 1024 * 1024 {
+        t.Errorf("allocated %v bytes", allocated)
+    }
+
+    // The sample whose offset overflows.
+    if _, err := ReadSample(readerAt{bytes.NewReader(b)}, &Mp4Sample{Offset: 1 << 63, Size: 16}); err == nil {
+        t.Error("the overflow offset should fail")
+    }
+    // The samples are read by the reader without size.
+    samples, _ := moov.Tracks()[1].Samples()
+    if data, err := ReadSample(readerAt{bytes.NewReader(b)}, samples[3]); err != nil || !bytes.Equal(data, bytes.Repeat([]byte{2, 3}, 4)) {
+        t.Errorf("sample %v, err is %v", data, err)
+    }
+}
+
+func TestSampleIterator(t *testing.T) {
+    b := buildAvFile(false)
+    moov, err := parseFile(t, b).Moov()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    cases := []struct {
+        order int
+        // The track id and sample index of the first samples.
+        first [][2]int
+    }{
+        // The chunks of 3 video samples and 4 audio samples are interleaved.
+        {SrsMp4SampleOrderFile, [][2]int{{1, 0}, {1, 1}, {1, 2}, {2, 0}, {2, 1}, {2, 2}, {2, 3}, {1, 3}}},
+        // The video is 40ms per sample, the audio is 1024/48000s per sample, the video first for the same time.
+        {SrsMp4SampleOrderDts, [][2]int{{1, 0}, {2, 0}, {2, 1}, {1, 1}, {2, 2}, {2, 3}, {1, 2}, {2, 4}}},
+    }
+    for _, c := range cases {
+        it, err := NewMp4SampleIterator(bytes.NewReader(b), moov, c.order)
+        if err != nil {
+            t.Fatal(err)
+        }
+
+        var walked [][2]int
+        var prevOffset uint64
+        var prevTime float64
+        for {
+            trak, sample, data, err := it.Next()
+            if err == io.EOF {
+                break
+            }
+            if err != nil {
+                t.Fatalf("order %v: %v", c.order, err)
+            }
+
+            id := int(trackId(t, trak))
+            walked = append(walked, [2]int{id, sample.Index})
+            if !bytes.Equal(data, bytes.Repeat([]byte{uint8(id), uint8(sample.Index)}, sample.Index + 1)) {
+                t.Errorf("order %v: track %v sample %v is %v", c.order, id, sample.Index, data)
+            }
+
+            mdhd, _ := trak.Mdhd()
+            at := float64(sample.Dts) / float64(mdhd.TimeScale)
+            if c.order == SrsMp4SampleOrderFile && sample.Offset < prevOffset {
+                t.Errorf("order %v: offset %v before %v", c.order, sample.Offset, prevOffset)
+            } else if c.order == SrsMp4SampleOrderDts && at < prevTime {
+                t.Errorf("order %v: time %v before %v", c.order, at, prevTime)
+            }
+            prevOffset, prevTime = sample.Offset, at
+        }
+
+        if len(walked) != 25 {
+            t.Errorf("order %v: walked %v samples, expect 25", c.order, len(walked))
+            continue
+        }
+        for i, expect := range c.first {
+            if walked[i] != expect {
+                t.Errorf("order %v: the %vth is %v, expect %v", c.order, i, walked[i], expect)
+            }
+        }
+
+        // Keep io.EOF after all samples are walked.
+        if _, _, _, err := it.Next(); err != io.EOF {
+            t.Errorf("order %v: err is %v after the end", c.order, err)
+        }
+    }
+}
+
+func TestChunkOffsetBox(t *testing.T) {
+    cases := []struct {
+        name string
+        box []byte
+        offsets []uint64
+    }{
+        {"stco", stco(8, 0xffffffff), []uint64{8, 0xffffffff}},
+        {"co64", fullBox("co64", 0, 0, be(uint32(3), uint64(8), uint64(1) << 32, uint64(0xffffffffffff))), []uint64{8, 1 << 32, 0xffffffffffff}},
+    }
+    for _, c := range cases {
+        trak := buildTrak(t, stsc([3]uint32{1, 1, 1}), stsz(), stts(), c.box)
+        stco, err := trak.Stco()
+        if err != nil || FourCC(stco.Basic().BoxType) != c.name || stco.NbChunks() != len(c.offsets) {
+            t.Errorf("%v: box %+v, err is %v", c.name, stco, err)
+            continue
+        }
+        for i, offset := range c.offsets {
+            if stco.ChunkOffset(i) != offset {
+                t.Errorf("%v: chunk %v at %v, expect %v", c.name, i, stco.ChunkOffset(i), offset)
+            }
+        }
+    }
+
+    if _, err := buildTrak(t, stsc(), stsz(), stts()).Stco(); err == nil {
+        t.Error("the track without stco and co64 should fail")
+    }
+}
+
+func stz2(fieldSize uint8, count uint32, table ...byte) []byte {
+    return fullBox("stz2", 0, 0, make([]byte, 3), []byte{fieldSize}, be(count), table)
+}
+
+func TestCompactSampleSize(t *testing.T) {
+    cases := []struct {
+        name string
+        box []byte
+        sizes []uint32
+    }{
+        // The odd number of 4 bits entries, the last byte is padded.
+        {"4 bits", stz2(4, 3, 0x1f, 0x70), []uint32{1, 15, 7}},
+        {"8 bits", stz2(8, 3, 1, 0xff, 7), []uint32{1, 255, 7}},
+        {"16 bits", stz2(16, 2, 0x01, 0x00, 0xff, 0xff), []uint32{256, 65535}},
+    }
+    for _, c := range cases {
+        offsets := make([]uint32, len(c.sizes))
+        for i := range offsets {
+            offsets[i] = uint32(1000 * (i + 1))
+        }
+        trak := buildTrak(t, stsc([3]uint32{1, 1, 1}), stco(offsets...), stts([2]uint32{uint32(len(c.sizes)), 1}), c.box)
+
+        stsz, err := trak.Stsz()
+        if err != nil || FourCC(stsz.Basic().BoxType) != "stz2" || stsz.NbSamples() != len(c.sizes) {
+            t.Errorf("%v: box %+v, err is %v", c.name, stsz, err)
+            continue
+        }
+        samples, err := trak.Samples()
+        if err != nil {
+            t.Errorf("%v: %v", c.name, err)
+            continue
+        }
+        for i, size := range c.sizes {
+            if stsz.EntrySize(i) != size || samples[i].Size != size || samples[i].Offset != uint64(offsets[i]) {
+                t.Errorf("%v: sample %v size %v, %+v, expect %v", c.name, i, stsz.EntrySize(i), samples[i], size)
+            }
+        }
+
+        var w bytes.Buffer
+        if err := Encode(&w, stsz, nil); err != nil || !bytes.Equal(w.Bytes(), c.box) {
+            t.Errorf("%v: encode %x, expect %x, err is %v", c.name, w.Bytes(), c.box, err)
+        }
+    }
+
+    invalid := []struct {
+        name string
+        box []byte
+    }{
+        {"field size", stz2(12, 2, 0, 0, 0)},
+        {"overflow", stz2(16, 3, 0, 1, 0, 2)},
+    }
+    for _, c := range invalid {
+        if _, err := Parse(bytes.NewReader(c.box)); err == nil {
+            t.Errorf("%v: parse should fail", c.name)
+        }
+    }
+}
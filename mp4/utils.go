@@ -0,0 +1,64 @@
+package mp4
+
+import (
+    "encoding/binary"
+    "math"
+    "math/bits"
+    "time"
+)
+
+// intDataSize returns the size of the data required to represent the data when encoded.
+// It returns zero if the type cannot be implemented by the fast path in Read or Write.
+func uint64DataSize(data interface{}) uint64 {
+    switch data.(type) {
+    case int8, uint8, *int8, *uint8:
+        return uint64(1)
+    case int16, uint16, *int16, *uint16:
+        return uint64(2)
+    case int32, uint32, *int32, *uint32, float32, *float32:
+        return uint64(4)
+    case int64, uint64, *int64, *uint64, float64, *float64:
+        return uint64(8)
+    case []uint8:
+        arru8 := data.([]uint8)
+        return uint64(len(arru8))
+    }
+    return 0
+}
+
+func Bytes3ToUint32(b []byte) uint32 {
+    nb := []byte{}
+    nb = append(nb, 0)
+    nb = append(nb, b...)
+    return binary.BigEndian.Uint32(nb)
+}
+
+// Convert the box type or brand to its four characters, for example, 0x6d6f6f76 to "moov".
+func FourCC(v uint32) string {
+    b := make([]byte, 4)
+    binary.BigEndian.PutUint32(b, v)
+    return string(b)
+}
+
+// Convert the time v in timescale from to timescale to, in 128bits to avoid overflow.
+func rescale(v, from, to uint64) uint64 {
+    if from == 0 {
+        return 0
+    }
+    hi, lo := bits.Mul64(v, to)
+    if hi >= from {
+        return ^uint64(0)
+    }
+    q, _ := bits.Div64(hi, lo, from)
+    return q
+}
+
+// Convert the time in seconds since midnight, Jan. 1, 1904, in UTC, for example, the creation time of mvhd.
+// @remark The time.Duration overflows after 292 years, so it's converted by the unix time, and the seconds
+//      of 64 bits over math.MaxInt64 are clamped.
+func Mp4Time(seconds uint64) time.Time {
+    if seconds > math.MaxInt64 {
+        seconds = math.MaxInt64
+    }
+    return time.Unix(int64(seconds) - 2082844800, 0).UTC()
+}
@@ -0,0 +1,37 @@
+package mp4
+
+import (
+    "math"
+    "testing"
+    "time"
+)
+
+func TestMp4Time(t *testing.T) {
+    cases := []struct {
+        seconds uint64
+        expect time.Time
+    }{
+        {0, time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)},
+        {2082844800, time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)},
+        // The creation time of the test files.
+        {3600000000, time.Date(2018, time.January, 28, 16, 0, 0, 0, time.UTC)},
+        // The largest time of 32 bits, in 2040.
+        {math.MaxUint32, time.Date(2040, time.February, 6, 6, 28, 15, 0, time.UTC)},
+    }
+    for _, c := range cases {
+        if v := Mp4Time(c.seconds); !v.Equal(c.expect) || v.Location() != time.UTC {
+            t.Errorf("%v is %v, expect %v", c.seconds, v, c.expect)
+        }
+    }
+
+    // The time of 64 bits after 292 years, which overflows the time.Duration.
+    if v := Mp4Time(1 << 40); v.Unix() != 1 << 40 - 2082844800 || v.Year() != 36746 {
+        t.Errorf("1<<40 is %v", v)
+    }
+    // The seconds over math.MaxInt64 are clamped.
+    for _, seconds := range []uint64{math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64} {
+        if v := Mp4Time(seconds); v.Unix() != math.MaxInt64 - 2082844800 {
+            t.Errorf("%v is %v", seconds, v)
+        }
+    }
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+    "encoding/json"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "mime/multipart"
+    "net"
+    "net/http"
+    "os"
+    "strings"
+    "syscall"
+    "time"
+    ol "github.com/ossrs/go-oryx-lib/logger"
+    "github.com/panda1986/mp4_parser/mp4"
+)
+
+// The web api, which parses the uploaded mp4 or the remote one by url.
+type server struct {
+    client *http.Client
+    maxUpload int64
+}
+
+// Run the web api, the endpoints are:
+//      POST /parse, parse the uploaded mp4, in body or the "file" of multipart form.
+//      GET /parse?url=http://xxxx.mp4, parse the remote mp4 by range requests.
+//      GET|POST /boxes/{path}, the box of path, for example, /boxes/moov/trak[1]/mdia/minf/stbl.
+func serve(args []string) (err error) {
+    fs := flag.NewFlagSet("serve", flag.ExitOnError)
+    listen := fs.String("listen", ":8080", "the address to listen")
+    maxUpload := fs.Int64("max-upload", 512 * 1024 * 1024, "the max bytes of uploaded or remote mp4")
+    timeout := fs.Duration("timeout", 60 * time.Second, "the timeout of each request")
+    allowPrivate := fs.Bool("allow-private", false, "allow the url of loopback, private and link-local address")
+    fs.Parse(args)
+
+    v := &server{
+        client: newClient(*timeout, *allowPrivate),
+        maxUpload: *maxUpload,
+    }
+
+    mux := http.NewServeMux()
+    mux.HandleFunc("/parse", v.handleParse)
+    mux.HandleFunc("/boxes/", v.handleBoxes)
+
+    hs := &http.Server{
+        Addr: *listen,
+        Handler: http.TimeoutHandler(mux, *timeout, `{"error":"timeout"}`),
+        ReadHeaderTimeout: 10 * time.Second,
+        ReadTimeout: *timeout,
+        WriteTimeout: *timeout + 5 * time.Second,
+    }
+
+    ol.T(nil, fmt.Sprintf("serve web api at %v, max upload=%v, timeout=%v, allow private=%v", *listen, *maxUpload, *timeout, *allowPrivate))
+    return hs.ListenAndServe()
+}
+
+// The max redirects when fetching the remote mp4.
+const maxRedirects = 3
+
+// Create the client to fetch the remote mp4 of user, which refuses the loopback, private and link-local
+// destinations unless allowPrivate, including the ones redirected to, so the server never requests the
+// internal network or the metadata service of cloud, for example, 169.254.169.254.
+func newClient(timeout time.Duration, allowPrivate bool) *http.Client {
+    dialer := &net.Dialer{Timeout: 10 * time.Second}
+    if !allowPrivate {
+        dialer.Control = refusePrivate
+    }
+
+    return &http.Client{
+        Timeout: timeout,
+        Transport: &http.Transport{
+            // No proxy, the dialer must check the address of the origin server.
+            Proxy: nil,
+            DialContext: dialer.DialContext,
+            TLSHandshakeTimeout: 10 * time.Second,
+        },
+        CheckRedirect: func(req *http.Request, via []*http.Request) error {
+            if len(via) >= maxRedirects {
+                return fmt.Errorf("stopped after %v redirects", len(via))
+            }
+            if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+                return fmt.Errorf("redirect to %v not allowed, only http(s) supported", req.URL)
+            }
+            return nil
+        },
+    }
+}
+
+// The Control of dialer, which is called with the resolved address, so a hostname which resolves
+// to a private address is refused too.
+func refusePrivate(network, address string, c syscall.RawConn) error {
+    host, _, err := net.SplitHostPort(address)
+    if err != nil {
+        return err
+    }
+    if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+        return fmt.Errorf("destination %v not allowed", address)
+    }
+    return nil
+}
+
+// The blocks which are not in the public internet, besides the loopback, private and link-local ones.
+var nonPublicBlocks = []string{
+    "0.0.0.0/8", "100.64.0.0/10", "192.0.0.0/24", "198.18.0.0/15", "240.0.0.0/4", "64:ff9b::/96",
+}
+
+func isPublic(ip net.IP) bool {
+    if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+        ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+        return false
+    }
+    for _, block := range nonPublicBlocks {
+        if _, n, err := net.ParseCIDR(block); err == nil && n.Contains(ip) {
+            return false
+        }
+    }
+    return true
+}
+
+func (v *server) handleParse(w http.ResponseWriter, r *http.Request) {
+    f, code, err := v.load(w, r)
+    if err != nil {
+        v.error(w, code, err)
+        return
+    }
+    v.write(w, mp4.NewFileJSON(f))
+}
+
+func (v *server) handleBoxes(w http.ResponseWriter, r *http.Request) {
+    f, code, err := v.load(w, r)
+    if err != nil {
+        v.error(w, code, err)
+        return
+    }
+
+    box, err := f.Find(strings.TrimPrefix(r.URL.Path, "/boxes/"))
+    if err != nil {
+        v.error(w, http.StatusNotFound, err)
+        return
+    }
+    v.write(w, mp4.NewBoxJSON(box))
+}
+
+// Parse the mp4 of request, the url for GET or the body for POST.
+func (v *server) load(w http.ResponseWriter, r *http.Request) (f *mp4.File, code int, err error) {
+    switch r.Method {
+    case "GET":
+        url := r.URL.Query().Get("url")
+        if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+            return nil, http.StatusBadRequest, fmt.Errorf("invalid url %v, only http(s) supported", url)
+        }
+
+        var hr *mp4.HttpReader
+        if hr, err = mp4.NewHttpReader(v.client, url); err != nil {
+            return nil, http.StatusBadGateway, err
+        }
+        // The size is reported by the remote server, and the boxes except mdat are read into memory,
+        // which are in the file, so the file is limited by the max upload too.
+        if hr.Size() > v.maxUpload {
+            return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("remote mp4 size %v exceeds %v", hr.Size(), v.maxUpload)
+        }
+        if f, err = mp4.ParseAt(hr, hr.Size()); err != nil {
+            return nil, http.StatusUnprocessableEntity, err
+        }
+        return f, http.StatusOK, nil
+    case "POST":
+        r.Body = http.MaxBytesReader(w, r.Body, v.maxUpload)
+
+        // Parse the upload of the real size, so the size of box declared by the file is checked before
+        // it's read into memory, which is never the body of stream.
+        var body io.ReaderAt
+        var size int64
+        if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+            var file multipart.File
+            var header *multipart.FileHeader
+            if file, header, err = r.FormFile("file"); err != nil {
+                return nil, v.uploadCode(err), err
+            }
+            defer file.Close()
+            body, size = file, header.Size
+        } else {
+            var tmp *os.File
+            if tmp, err = os.CreateTemp("", "mp4_parser-*.mp4"); err != nil {
+                return nil, http.StatusInternalServerError, err
+            }
+            defer os.Remove(tmp.Name())
+            defer tmp.Close()
+
+            if size, err = io.Copy(tmp, r.Body); err != nil {
+                return nil, v.uploadCode(err), err
+            }
+            body = tmp
+        }
+
+        if f, err = mp4.ParseAt(body, size); err != nil {
+            return nil, v.uploadCode(err), err
+        }
+        return f, http.StatusOK, nil
+    }
+    return nil, http.StatusMethodNotAllowed, fmt.Errorf("method %v not allowed", r.Method)
+}
+
+func (v *server) uploadCode(err error) int {
+    var mbe *http.MaxBytesError
+    if errors.As(err, &mbe) {
+        return http.StatusRequestEntityTooLarge
+    }
+    return http.StatusBadRequest
+}
+
+func (v *server) write(w http.ResponseWriter, data interface{}) {
+    w.Header().Set("Content-Type", "application/json")
+    if err := json.NewEncoder(w).Encode(data); err != nil {
+        ol.E(nil, fmt.Sprintf("write response failed, err is %v", err))
+    }
+}
+
+func (v *server) error(w http.ResponseWriter, code int, err error) {
+    ol.W(nil, fmt.Sprintf("request failed, code=%v, err is %v", code, err))
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(code)
+    json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
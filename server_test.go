@@ -0,0 +1,203 @@
+package main
+
+import (
+    "bytes"
+    "io"
+    "mime/multipart"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "runtime"
+    "testing"
+    "time"
+    ol "github.com/ossrs/go-oryx-lib/logger"
+)
+
+func init() {
+    ol.Switch(io.Discard)
+}
+
+// The mp4 of only a ftyp box.
+var testFtyp = []byte{0, 0, 0, 16, 'f', 't', 'y', 'p', 'i', 's', 'o', 'm', 0, 0, 2, 0}
+
+func TestIsPublic(t *testing.T) {
+    cases := []struct {
+        ip string
+        public bool
+    }{
+        {"8.8.8.8", true},
+        {"2001:4860:4860::8888", true},
+        {"127.0.0.1", false},
+        {"::1", false},
+        {"10.1.2.3", false},
+        {"172.16.0.1", false},
+        {"192.168.1.1", false},
+        {"169.254.169.254", false},
+        {"fe80::1", false},
+        {"fd00::1", false},
+        {"0.0.0.0", false},
+        {"::", false},
+        {"100.64.0.1", false},
+        {"224.0.0.1", false},
+        {"::ffff:127.0.0.1", false},
+        {"::ffff:169.254.169.254", false},
+    }
+    for _, c := range cases {
+        if public := isPublic(net.ParseIP(c.ip)); public != c.public {
+            t.Errorf("ip %v public=%v, expect %v", c.ip, public, c.public)
+        }
+    }
+}
+
+func newTestServer(allowPrivate bool) *httptest.Server {
+    v := &server{client: newClient(5 * time.Second, allowPrivate), maxUpload: 1024 * 1024}
+    mux := http.NewServeMux()
+    mux.HandleFunc("/parse", v.handleParse)
+    return httptest.NewServer(mux)
+}
+
+func parseURL(t *testing.T, api *httptest.Server, target string) int {
+    t.Helper()
+    res, err := http.Get(api.URL + "/parse?url=" + url.QueryEscape(target))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer res.Body.Close()
+    return res.StatusCode
+}
+
+func TestParseRefusePrivate(t *testing.T) {
+    var requested bool
+    origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        requested = true
+        http.ServeContent(w, r, "test.mp4", time.Time{}, bytes.NewReader(testFtyp))
+    }))
+    defer origin.Close()
+
+    api := newTestServer(false)
+    defer api.Close()
+    if code := parseURL(t, api, origin.URL); code != http.StatusBadGateway {
+        t.Errorf("the loopback url responds %v", code)
+    }
+    if code := parseURL(t, api, "http://169.254.169.254/latest/meta-data"); code != http.StatusBadGateway {
+        t.Errorf("the link-local url responds %v", code)
+    }
+    if requested {
+        t.Error("the loopback origin is requested")
+    }
+
+    allowed := newTestServer(true)
+    defer allowed.Close()
+    if code := parseURL(t, allowed, origin.URL); code != http.StatusOK {
+        t.Errorf("the allowed loopback url responds %v", code)
+    }
+}
+
+func TestParseRedirects(t *testing.T) {
+    var nbRequests int
+    origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        nbRequests++
+        http.Redirect(w, r, "/again", http.StatusFound)
+    }))
+    defer origin.Close()
+
+    api := newTestServer(true)
+    defer api.Close()
+    if code := parseURL(t, api, origin.URL); code != http.StatusBadGateway {
+        t.Errorf("the redirect loop responds %v", code)
+    }
+    if nbRequests != maxRedirects {
+        t.Errorf("requested %v times, expect %v", nbRequests, maxRedirects)
+    }
+}
+
+func postParse(t *testing.T, api *httptest.Server, contentType string, body []byte) int {
+    t.Helper()
+    res, err := http.Post(api.URL + "/parse", contentType, bytes.NewReader(body))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer res.Body.Close()
+    return res.StatusCode
+}
+
+// Post the file in the multipart form.
+func postMultipart(t *testing.T, api *httptest.Server, data []byte) int {
+    t.Helper()
+    var b bytes.Buffer
+    mw := multipart.NewWriter(&b)
+    fw, err := mw.CreateFormFile("file", "test.mp4")
+    if err != nil {
+        t.Fatal(err)
+    }
+    fw.Write(data)
+    mw.Close()
+    return postParse(t, api, mw.FormDataContentType(), b.Bytes())
+}
+
+func TestParseUpload(t *testing.T) {
+    api := newTestServer(false)
+    defer api.Close()
+
+    if code := postParse(t, api, "video/mp4", testFtyp); code != http.StatusOK {
+        t.Errorf("the body responds %v", code)
+    }
+    if code := postMultipart(t, api, testFtyp); code != http.StatusOK {
+        t.Errorf("the multipart form responds %v", code)
+    }
+
+    // The upload over the max upload of 1MB.
+    large := append(append([]byte{}, testFtyp...), 0, 0x20, 0, 0, 'f', 'r', 'e', 'e')
+    large = append(large, make([]byte, 2 * 1024 * 1024 - 8)...)
+    if code := postParse(t, api, "video/mp4", large); code != http.StatusRequestEntityTooLarge {
+        t.Errorf("the large body responds %v", code)
+    }
+    if code := postMultipart(t, api, large); code != http.StatusRequestEntityTooLarge {
+        t.Errorf("the large multipart form responds %v", code)
+    }
+}
+
+func TestParseUploadHugeBox(t *testing.T) {
+    api := newTestServer(false)
+    defer api.Close()
+
+    // The mp4 of 80 bytes, the moov and its hvcC declare about 2GB, which is never allocated.
+    b := append(append([]byte{}, testFtyp...), 0x7f, 0xff, 0xff, 0xf0, 'm', 'o', 'o', 'v', 0x7f, 0xff, 0xff, 0xe0, 'h', 'v', 'c', 'C')
+    b = append(b, make([]byte, 80 - len(b))...)
+
+    var before, after runtime.MemStats
+    runtime.ReadMemStats(&before)
+    if code := postParse(t, api, "video/mp4", b); code != http.StatusBadRequest {
+        t.Errorf("the huge box responds %v", code)
+    }
+    if code := postMultipart(t, api, b); code != http.StatusBadRequest {
+        t.Errorf("the huge box of multipart form responds %v", code)
+    }
+    runtime.ReadMemStats(&after)
+    if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64 * 1024 * 1024 {
+        t.Errorf("allocated %v bytes", allocated)
+    }
+}
+
+func TestParseRemoteTooLarge(t *testing.T) {
+    // The remote server reports the size of 1TB.
+    var nbRequests int
+    origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        nbRequests++
+        w.Header().Set("Content-Range", "bytes 0-0/1099511627776")
+        w.WriteHeader(http.StatusPartialContent)
+        w.Write(testFtyp[:1])
+    }))
+    defer origin.Close()
+
+    api := newTestServer(true)
+    defer api.Close()
+    if code := parseURL(t, api, origin.URL); code != http.StatusRequestEntityTooLarge {
+        t.Errorf("the large remote file responds %v", code)
+    }
+    // Only the request for the size.
+    if nbRequests != 1 {
+        t.Errorf("requested %v times", nbRequests)
+    }
+}